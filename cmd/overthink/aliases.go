@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// aliasesFile is the name of the model aliases file in overthink's
+// configuration directory.
+const aliasesFile = "aliases.json"
+
+// mustAliases loads the user's model aliases, exiting with an error if the
+// file exists but cannot be read.
+func mustAliases() map[string]string {
+	aliases, err := loadAliases()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		os.Exit(1)
+	}
+	return aliases
+}
+
+// loadAliases reads the model aliases from aliases.json in overthink's
+// configuration directory: a JSON object mapping each alias to an Ollama
+// model, e.g. {"fast": "phi3:mini"}. A missing file means no aliases.
+func loadAliases() (map[string]string, error) {
+	dir := configDir("")
+	if dir == "" {
+		return nil, nil
+	}
+	path := filepath.Join(dir, aliasesFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var aliases map[string]string
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return aliases, nil
+}
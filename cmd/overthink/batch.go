@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/rishichawda/overthinker/internal/backend"
+	"github.com/rishichawda/overthinker/internal/chain"
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+const batchUsageText = `overthink batch -- overthink many questions at once
+
+Usage:
+  overthink batch [flags] <questions.txt | questions.csv | ->
+
+The input has one question per line ("-" reads stdin). A CSV file (or any
+input with --csv) must have a header row with a "question" column and may
+have an "id" column; otherwise questions are numbered by line.
+
+Flags:
+  --thinker <spec>    Thinker or fallback chain, as for a single question
+  --timeout <dur>     Time budget for each thinker attempt (default 2m)
+  --retries <n>       Extra attempts after a transient failure (default 2)
+  --workers <n>       Questions analyzed concurrently (default 4)
+  --out-dir <dir>     Write <id>.json per question instead of NDJSON on stdout
+                      (a repeated id gets <id>-2.json, <id>-3.json, ...)
+  --csv               Parse the input as CSV regardless of its name
+  --intensity <1-5>   Drama from 1 to 5, as for a single question
+  --persona <name>    Narrator, as for a single question
+  --pull              Pull missing Ollama models first, with a progress bar
+  --seed <n>          Seed, as for a single question; also --temperature,
+                      --top-p, --num-ctx, --num-predict and --keep-alive
+  --verbose, --debug  Log backend activity to stderr, as for a single question
+  --trace-file <f>    Append all backend traffic to a file
+
+Examples:
+  overthink batch questions.txt > results.ndjson
+  overthink batch --thinker llama3 --workers 2 --out-dir issue-42 questions.csv
+  cat questions.txt | overthink batch -
+`
+
+// defaultBatchWorkers is the default size of the batch worker pool.
+const defaultBatchWorkers = 4
+
+// batchQuestion is one input row.
+type batchQuestion struct {
+	ID       string
+	Question string
+	// File is the name of the question's --out-dir file.
+	File string
+}
+
+// batchRecord is the JSON written for each question. Exactly one of Result
+// and Error is set; Fallbacks lists thinkers that failed before the one that
+// answered.
+type batchRecord struct {
+	ID        string                 `json:"id"`
+	Question  string                 `json:"question"`
+	Thinker   string                 `json:"thinker,omitempty"`
+	LatencyMS int64                  `json:"latency_ms"`
+	Fallbacks []string               `json:"fallbacks,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	Result    *engine.AnalysisResult `json:"result,omitempty"`
+
+	file string
+}
+
+// runBatch implements the "batch" subcommand. Questions are analyzed by a
+// bounded pool of workers; each result is written as soon as it is ready, and
+// a failed question is recorded without stopping the others.
+func runBatch(args []string) {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	thinkerFlag := fs.String("thinker", "", "thinker spec or fallback chain")
+	timeoutFlag := fs.Duration("timeout", chain.DefaultTimeout, "time budget for each thinker attempt")
+	retriesFlag := fs.Int("retries", chain.DefaultRetries, "extra attempts after a transient failure")
+	workersFlag := fs.Int("workers", defaultBatchWorkers, "questions analyzed concurrently")
+	outDirFlag := fs.String("out-dir", "", "directory for one JSON file per question")
+	csvFlag := fs.Bool("csv", false, "parse the input as CSV")
+	intensityFlag := fs.String("intensity", "3", "drama from 1 to 5")
+	personaFlag := personaFlag(fs)
+	generation := addGenerationFlags(fs)
+	pullFlag := fs.Bool("pull", false, "pull missing Ollama models before the batch")
+	logging := addLogFlags(fs)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, batchUsageText) }
+	fs.Parse(args)
+	logging.setup()
+
+	if fs.NArg() != 1 || *workersFlag < 1 || *retriesFlag < 0 {
+		fs.Usage()
+		exit(1)
+	}
+
+	questions, err := readBatchInput(fs.Arg(0), *csvFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		exit(1)
+	}
+	if *outDirFlag != "" {
+		if err := os.MkdirAll(*outDirFlag, 0o755); err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			exit(1)
+		}
+		nameBatchFiles(questions)
+	}
+
+	aliases := mustAliases()
+	if *pullFlag {
+		pullModels(backend.Split(*thinkerFlag), aliases, false)
+	}
+
+	thinker, err := backend.NewChain(backend.Split(*thinkerFlag), backend.Options{
+		Timeout:    *timeoutFlag,
+		Retries:    *retriesFlag,
+		Intensity:  mustIntensity(*intensityFlag),
+		Persona:    mustPersona(*personaFlag),
+		Generation: generation.mustParse(),
+		Aliases:    aliases,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		exit(1)
+	}
+
+	jobs := make(chan batchQuestion)
+	records := make(chan batchRecord)
+	var wg sync.WaitGroup
+	for i := 0; i < *workersFlag; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q := range jobs {
+				records <- analyzeBatchQuestion(thinker, q)
+			}
+		}()
+	}
+	go func() {
+		for _, q := range questions {
+			jobs <- q
+		}
+		close(jobs)
+		wg.Wait()
+		close(records)
+	}()
+
+	progress := newBatchProgress(len(questions))
+	encoder := json.NewEncoder(os.Stdout)
+	for rec := range records {
+		if *outDirFlag != "" {
+			err = writeBatchFile(*outDirFlag, rec)
+		} else {
+			err = encoder.Encode(rec)
+		}
+		if err != nil {
+			progress.finish()
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			exit(1)
+		}
+		progress.add(rec.Error != "")
+	}
+	progress.finish()
+
+	if progress.failed > 0 {
+		fmt.Fprintf(os.Stderr, "overthink: %d of %d questions failed\n", progress.failed, progress.total)
+		exit(1)
+	}
+}
+
+// analyzeBatchQuestion runs one question and captures the outcome, including
+// a failure, as a record.
+func analyzeBatchQuestion(thinker engine.Thinker, q batchQuestion) batchRecord {
+	rec := batchRecord{ID: q.ID, Question: q.Question, file: q.File}
+	start := time.Now()
+	result, err := thinker.Analyze(q.Question)
+	rec.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		rec.Error = err.Error()
+		return rec
+	}
+	for _, a := range result.Attempts {
+		if a.Err != nil {
+			rec.Fallbacks = append(rec.Fallbacks, fmt.Sprintf("%s: %v", a.Thinker, a.Err))
+		} else {
+			rec.Thinker = a.Thinker
+		}
+	}
+	rec.Result = result
+	recordHistory(rec.Thinker, result)
+	return rec
+}
+
+// writeBatchFile writes rec into dir, under the name nameBatchFiles gave its
+// question.
+func writeBatchFile(dir string, rec batchRecord) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, rec.file), append(data, '\n'), 0o644)
+}
+
+// nameBatchFiles assigns each question its --out-dir file, in input order.
+// IDs that would share a file -- duplicates, IDs that differ only in
+// characters a file name cannot hold, or only in case -- get a numeric
+// suffix, so that no result overwrites another.
+func nameBatchFiles(questions []batchQuestion) {
+	used := make(map[string]bool)
+	for i := range questions {
+		base := batchFileName(questions[i].ID)
+		name := base
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[strings.ToLower(name)] = true
+		questions[i].File = name + ".json"
+	}
+}
+
+// batchFileName makes an id safe to use as a file name: no path separators,
+// and no leading dot that would hide the file.
+func batchFileName(id string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, id)
+	if name == "" || name[0] == '.' {
+		name = "_" + name
+	}
+	return name
+}
+
+// readBatchInput reads questions from path, or stdin for "-". Files ending in
+// .csv, or any input when asCSV is set, are parsed as CSV.
+func readBatchInput(path string, asCSV bool) ([]batchQuestion, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+		asCSV = asCSV || strings.EqualFold(filepath.Ext(path), ".csv")
+	}
+
+	var (
+		questions []batchQuestion
+		err       error
+	)
+	if asCSV {
+		questions, err = readBatchCSV(r)
+	} else {
+		questions, err = readBatchLines(r)
+	}
+	if err == nil && len(questions) == 0 {
+		err = errors.New("no questions in input")
+	}
+	return questions, err
+}
+
+// readBatchLines reads one question per line, skipping blank lines. Each
+// question's id is its line number.
+func readBatchLines(r io.Reader) ([]batchQuestion, error) {
+	var questions []batchQuestion
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		if q := strings.TrimSpace(scanner.Text()); q != "" {
+			questions = append(questions, batchQuestion{ID: strconv.Itoa(n), Question: q})
+		}
+	}
+	return questions, scanner.Err()
+}
+
+// readBatchCSV reads a CSV with a header row naming a "question" column and,
+// optionally, an "id" column. Rows without an id are numbered by position.
+func readBatchCSV(r io.Reader) ([]batchQuestion, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	idCol, questionCol := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "id":
+			idCol = i
+		case "question":
+			questionCol = i
+		}
+	}
+	if questionCol < 0 {
+		return nil, errors.New(`CSV header has no "question" column`)
+	}
+
+	var questions []batchQuestion
+	for row := 1; ; row++ {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if questionCol >= len(fields) || strings.TrimSpace(fields[questionCol]) == "" {
+			continue
+		}
+		id := strconv.Itoa(row)
+		if idCol >= 0 && idCol < len(fields) && strings.TrimSpace(fields[idCol]) != "" {
+			id = strings.TrimSpace(fields[idCol])
+		}
+		questions = append(questions, batchQuestion{ID: id, Question: strings.TrimSpace(fields[questionCol])})
+	}
+	return questions, nil
+}
+
+// batchProgress reports progress on stderr, redrawing a single line when
+// stderr is a terminal and staying silent otherwise.
+type batchProgress struct {
+	total, done, failed int
+	live                bool
+	start               time.Time
+}
+
+func newBatchProgress(total int) *batchProgress {
+	p := &batchProgress{total: total, live: term.IsTerminal(int(os.Stderr.Fd())), start: time.Now()}
+	p.draw()
+	return p
+}
+
+func (p *batchProgress) add(failed bool) {
+	p.done++
+	if failed {
+		p.failed++
+	}
+	p.draw()
+}
+
+func (p *batchProgress) draw() {
+	if !p.live {
+		return
+	}
+	line := fmt.Sprintf("\roverthinking %d/%d", p.done, p.total)
+	if p.failed > 0 {
+		line += fmt.Sprintf(", %d failed", p.failed)
+	}
+	fmt.Fprintf(os.Stderr, "%s  (%s)\033[K", line, time.Since(p.start).Round(time.Second))
+}
+
+func (p *batchProgress) finish() {
+	if p.live {
+		fmt.Fprintln(os.Stderr)
+		p.live = false
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNameBatchFiles(t *testing.T) {
+	ids := []string{"q1", "q1", "a/b", "a_b", "Q1", "q1-2", "q1", ".hidden"}
+	questions := make([]batchQuestion, len(ids))
+	for i, id := range ids {
+		questions[i] = batchQuestion{ID: id}
+	}
+	nameBatchFiles(questions)
+
+	var got []string
+	for _, q := range questions {
+		got = append(got, q.File)
+	}
+	want := []string{"q1.json", "q1-2.json", "a_b.json", "a_b-2.json", "Q1-3.json", "q1-2-2.json", "q1-4.json", "_.hidden.json"}
+	if !slices.Equal(got, want) {
+		t.Errorf("files = %q, want %q", got, want)
+	}
+}
+
+func TestDuplicateIDsKeepEveryResult(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "questions.csv")
+	if err := os.WriteFile(input, []byte("id,question\n7,Should I?\n7,Should I not?\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	questions, err := readBatchInput(input, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameBatchFiles(questions)
+
+	dir := t.TempDir()
+	for _, q := range questions {
+		rec := batchRecord{ID: q.ID, Question: q.Question, file: q.File}
+		if err := writeBatchFile(dir, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(questions) {
+		t.Fatalf("%d files written for %d questions", len(entries), len(questions))
+	}
+	for _, q := range questions {
+		data, err := os.ReadFile(filepath.Join(dir, q.File))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), q.Question) {
+			t.Errorf("%s does not hold %q:\n%s", q.File, q.Question, data)
+		}
+	}
+}
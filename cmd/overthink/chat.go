@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/rishichawda/overthinker/internal/backend"
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/ollama"
+)
+
+// followUpPrompt is shown on stderr when waiting for the next follow-up.
+const followUpPrompt = "follow-up (empty line to stop)> "
+
+// runConversation holds a multi-turn session with an Ollama model. The
+// question, if any, is asked first; with interactive set, further follow-ups
+// are read from in until an empty line or EOF. When sessionPath is set the
+// conversation is resumed from that file if it exists and saved after every
+// turn. opts supplies the request timeout and any context for the model.
+func runConversation(question, model, sessionPath string, interactive bool, opts backend.Options, in io.Reader, formatter *engine.Formatter) {
+	conv, err := openConversation(sessionPath, model)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		exit(1)
+	}
+	if model == "" {
+		model = conv.Model
+	}
+	if !backend.OllamaModel(model) {
+		fmt.Fprintln(os.Stderr, "overthink: follow-up questions need a single Ollama model (--thinker <model>)")
+		exit(1)
+	}
+
+	client := ollama.NewClient(model)
+	client.Context = opts.Context
+	client.Intensity = opts.Intensity
+	client.Persona = opts.Persona
+	client.Generation = opts.Generation
+	client.Aliases = opts.Aliases
+	client.Filler = opts.LocalEngine(nil)
+	if opts.Timeout > 0 {
+		client.Timeout = opts.Timeout
+	}
+
+	printedHeader := false
+	ask := func(q string) bool {
+		result, err := client.Converse(conv, q)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			return false
+		}
+		recordHistory(model, result)
+		addRiskProfile(result, q, opts.Context)
+		if !printedHeader {
+			formatter.PrintModelHeader(model)
+			printedHeader = true
+		}
+		formatter.Print(result)
+		if sessionPath != "" {
+			if err := conv.Save(sessionPath); err != nil {
+				fmt.Fprintf(os.Stderr, "overthink: could not save session: %v\n", err)
+			}
+		}
+		return true
+	}
+
+	if question != "" && !ask(question) && !interactive {
+		exit(1)
+	}
+	if !interactive {
+		return
+	}
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(os.Stderr, followUpPrompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(os.Stderr)
+			return
+		}
+		q := strings.TrimSpace(scanner.Text())
+		if q == "" {
+			return
+		}
+		ask(q)
+	}
+}
+
+// openConversation resumes the session saved at path, or starts a new one
+// when path is empty or does not exist yet.
+func openConversation(path, model string) (*ollama.Conversation, error) {
+	if path == "" {
+		return ollama.NewConversation(model), nil
+	}
+	conv, err := ollama.LoadConversation(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ollama.NewConversation(model), nil
+	}
+	return conv, err
+}
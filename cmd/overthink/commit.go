@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/gitrepo"
+	"github.com/rishichawda/overthinker/internal/local"
+)
+
+const commitUsageText = `overthink commit -- overthink your staged change
+
+Usage:
+  overthink commit [flags]
+
+Reads the staged diff and the last commit message from the repository in the
+current directory (or the last commit itself when nothing is staged) and
+overanalyzes the change: its size, the files it touches and any scary paths
+such as migrations, CI pipelines or dependency manifests.
+
+Flags:
+  --seed <n>          Reproduce the same analysis every time
+                      ($OVERTHINK_SEED)
+  --brief             Condensed report: title, risk index, top outcome
+  --intensity <1-5>   Drama from 1 (mildly concerned) to 5 (full Greek
+                      tragedy) (default 3)
+  --json              Print the analysis as JSON instead of the report
+  --theme <name>      Color theme, as for a single question
+  --a11y              Screen-reader friendly plain text output
+  --install-hook      Install a prepare-commit-msg hook that appends the
+                      analysis to every commit message as comments
+  --hook <file>       Hook mode: judge the message in <file> and append the
+                      analysis to it (used by the installed hook)
+
+Examples:
+  git add -p && overthink commit
+  overthink commit --json | jq .risk_index
+  overthink commit --install-hook
+`
+
+// hookName is the git hook overthink installs itself as.
+const hookName = "prepare-commit-msg"
+
+// hookMarker identifies a hook script written by --install-hook, so that it
+// can be replaced safely but a user's own hook is never overwritten.
+const hookMarker = "# Installed by overthink commit --install-hook."
+
+// hookScript runs overthink for ordinary commits and never blocks them. It
+// stays out of messages given with -m, -F, -C and friends: git only strips
+// comment lines from messages that are edited, so the report would end up in
+// the commit itself.
+const hookScript = `#!/bin/sh
+` + hookMarker + `
+case "$2" in merge|squash|message|commit) exit 0 ;; esac
+command -v overthink >/dev/null 2>&1 || exit 0
+overthink commit --hook "$1" || true
+`
+
+// runCommit implements the "commit" subcommand.
+func runCommit(args []string) {
+	fs := flag.NewFlagSet("commit", flag.ExitOnError)
+	seedFlag := addSeedFlag(fs, "fixed seed for a reproducible analysis")
+	briefFlag := fs.Bool("brief", false, "print a condensed report")
+	jsonFlag := fs.Bool("json", false, "print the analysis as JSON")
+	installFlag := fs.Bool("install-hook", false, "install the prepare-commit-msg hook")
+	hookFlag := fs.String("hook", "", "commit message file to judge and annotate")
+	themeFlag := themeFlag(fs)
+	intensityFlag := fs.String("intensity", "3", "drama from 1 to 5")
+	a11yFlag := fs.Bool("a11y", false, "screen-reader friendly plain text output")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, commitUsageText) }
+	fs.Parse(args)
+	applyTheme(*themeFlag)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if _, err := generationConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		os.Exit(1)
+	}
+	seed, err := parseSeed(*seedFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *installFlag {
+		path, err := installHook(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stdout, "installed %s\n", path)
+		return
+	}
+
+	change, err := gitrepo.Read(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		os.Exit(1)
+	}
+
+	message, comment := "", ""
+	if *hookFlag != "" {
+		if message, comment, err = gitrepo.ReadMessageFile(".", *hookFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	thinker := local.NewCommit(change)
+	if seed != nil {
+		thinker.Seed, thinker.Seeded = int64(*seed), true
+	}
+	thinker.Intensity = mustIntensity(*intensityFlag)
+	result, _ := thinker.Analyze(message)
+	recordHistory("commit", result)
+
+	formatter := engine.NewFormatter(os.Stdout)
+	formatter.SetAccessible(*a11yFlag)
+	switch {
+	case *hookFlag != "":
+		if err := appendHookReport(*hookFlag, comment, result); err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			os.Exit(1)
+		}
+	case *jsonFlag:
+		printJSON(result)
+	case *briefFlag:
+		formatter.PrintBrief(result)
+	default:
+		formatter.Print(result)
+	}
+}
+
+// appendHookReport appends a plain-text summary of result to the commit
+// message file as lines starting with comment, which git strips from the
+// final message.
+func appendHookReport(path, comment string, result *engine.AnalysisResult) error {
+	var sb strings.Builder
+	sb.WriteString(comment + "\n")
+	fmt.Fprintf(&sb, "%s overthink: %s\n", comment, result.Title)
+	fmt.Fprintf(&sb, "%s Emotional Risk Index: %d/100\n", comment, result.RiskIndex)
+	if p, ok := (engine.Run{Result: result}).TopProbability(); ok {
+		fmt.Fprintf(&sb, "%s Most likely: %.1f%% %s\n", comment, p.Percentage, p.Label)
+	}
+	fmt.Fprintf(&sb, "%s --> %s\n", comment, result.ClosingLine)
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(sb.String()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// installHook writes the prepare-commit-msg hook into the repository
+// containing dir and returns its path. A hook that overthink did not write is
+// left alone.
+func installHook(dir string) (string, error) {
+	path, err := gitrepo.HookPath(dir, hookName)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil && !strings.Contains(string(existing), hookMarker):
+		return "", fmt.Errorf("%s already exists; add \"overthink commit --hook \\\"$1\\\"\" to it by hand", path)
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(hookScript), 0o755); err != nil {
+		return "", err
+	}
+	// WriteFile keeps the mode of an existing file; make sure it runs.
+	return path, os.Chmod(path, 0o755)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// newGitRepo initialises an empty repository in a temporary directory.
+func newGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	dir := t.TempDir()
+	gitIn(t, dir, "init", "-q")
+	gitIn(t, dir, "config", "user.name", "Test")
+	gitIn(t, dir, "config", "user.email", "test@example.com")
+	return dir
+}
+
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+func TestInstallHook(t *testing.T) {
+	dir := newGitRepo(t)
+	path, err := installHook(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("hook mode = %v, want executable", info.Mode())
+	}
+
+	// Our own hook is replaced; anyone else's is left alone.
+	if _, err := installHook(dir); err != nil {
+		t.Errorf("reinstalling: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := installHook(dir); err == nil {
+		t.Error("installHook replaced a hook it did not write")
+	}
+}
+
+// TestHookSkipsMessagesThatAreNotEdited commits through the installed hook,
+// with a stand-in overthink on PATH that marks the message it is given.
+func TestHookSkipsMessagesThatAreNotEdited(t *testing.T) {
+	dir := newGitRepo(t)
+	if _, err := installHook(dir); err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	stub := "#!/bin/sh\necho annotated >> \"$3\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "overthink"), []byte(stub), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("GIT_EDITOR", "true")
+
+	template := filepath.Join(t.TempDir(), "template")
+	if err := os.WriteFile(template, []byte("From a template\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, dir, "commit", "-q", "--allow-empty", "-t", template)
+	if got := gitIn(t, dir, "log", "-1", "--format=%B"); !strings.Contains(got, "annotated") {
+		t.Errorf("edited message was not annotated: %q", got)
+	}
+
+	gitIn(t, dir, "commit", "-q", "--allow-empty", "-m", "From -m")
+	if got := gitIn(t, dir, "log", "-1", "--format=%B"); strings.Contains(got, "annotated") {
+		t.Errorf("-m message was annotated: %q", got)
+	}
+}
+
+func TestAppendHookReportUsesCommentString(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte("Fix it\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result := &engine.AnalysisResult{
+		Title:         "THE FIX",
+		RiskIndex:     42,
+		Probabilities: []engine.Probability{{Label: "chance of regret", Percentage: 100}},
+		ClosingLine:   "Ship it.",
+	}
+	if err := appendHookReport(path, ";", result); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if lines[0] != "Fix it" {
+		t.Errorf("message changed: %q", lines[0])
+	}
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, ";") {
+			t.Errorf("report line %q is not commented with ;", line)
+		}
+	}
+}
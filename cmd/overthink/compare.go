@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rishichawda/overthinker/internal/backend"
+	"github.com/rishichawda/overthinker/internal/chain"
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+const compareUsageText = `overthink compare -- run one question against several thinkers
+
+Usage:
+  overthink compare --thinker <a,b,...> [flags] "<your question>"
+
+Flags:
+  --thinker <list>    Comma-separated thinkers to compare (e.g. llama3,mistral,local)
+  --timeout <dur>     Per-thinker time budget (default 2m)
+  --layout <mode>     columns or panels (default columns)
+  --intensity <1-5>   Drama for every thinker, as for a single question
+  --persona <name>    Narrator for every thinker, as for a single question
+  --pull              Pull missing Ollama models first, with a progress bar
+  --seed <n>          Seed, as for a single question; also --temperature,
+                      --top-p, --num-ctx, --num-predict and --keep-alive
+  --theme <name>      Color theme, as for a single question
+  --a11y              Screen-reader friendly plain text output
+  --verbose, --debug  Log backend activity to stderr, as for a single question
+  --trace-file <f>    Append all backend traffic to a file
+
+Example:
+  overthink compare --thinker llama3,mistral,local "Should I text my ex?"
+`
+
+// runCompare implements the "compare" subcommand: every thinker analyzes the
+// question concurrently and the results are rendered together with a summary
+// table of risk index, top probability and latency.
+func runCompare(args []string) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	thinkerFlag := fs.String("thinker", "", "comma-separated thinkers to compare")
+	timeoutFlag := fs.Duration("timeout", chain.DefaultTimeout, "per-thinker time budget")
+	layoutFlag := fs.String("layout", string(engine.LayoutColumns), "columns or panels")
+	themeFlag := themeFlag(fs)
+	intensityFlag := fs.String("intensity", "3", "drama from 1 to 5")
+	personaFlag := personaFlag(fs)
+	generation := addGenerationFlags(fs)
+	pullFlag := fs.Bool("pull", false, "pull missing Ollama models before comparing")
+	logging := addLogFlags(fs)
+	a11yFlag := fs.Bool("a11y", false, "screen-reader friendly plain text output")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, compareUsageText) }
+	fs.Parse(args)
+	logging.setup()
+	applyTheme(*themeFlag)
+
+	specs := backend.Split(*thinkerFlag)
+	question := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if len(specs) == 0 || question == "" {
+		fs.Usage()
+		exit(1)
+	}
+
+	layout := engine.Layout(*layoutFlag)
+	if layout != engine.LayoutColumns && layout != engine.LayoutPanels {
+		fmt.Fprintf(os.Stderr, "overthink: unknown layout %q (want columns or panels)\n", *layoutFlag)
+		exit(1)
+	}
+
+	aliases := mustAliases()
+	if *pullFlag {
+		pullModels(specs, aliases, *a11yFlag)
+	}
+
+	narrator := mustPersona(*personaFlag)
+	thinkers, err := backend.NewAll(specs, backend.Options{
+		Timeout:    *timeoutFlag,
+		Intensity:  mustIntensity(*intensityFlag),
+		Persona:    narrator,
+		Generation: generation.mustParse(),
+		Aliases:    aliases,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		exit(1)
+	}
+
+	runs := engine.AnalyzeAll(context.Background(), thinkers, question, *timeoutFlag)
+	for _, r := range runs {
+		if r.Err == nil {
+			recordHistory(r.Name, r.Result)
+		}
+	}
+	formatter := engine.NewFormatter(os.Stdout)
+	formatter.SetASCII(!unicodeSupported())
+	formatter.SetAccessible(*a11yFlag)
+	if narrator != nil {
+		formatter.SetHeadings(narrator.Headings)
+	}
+	formatter.PrintComparison(runs, layout, terminalWidth())
+}
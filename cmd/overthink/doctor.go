@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/rishichawda/overthinker/internal/backend"
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/history"
+	"github.com/rishichawda/overthinker/internal/ollama"
+	"github.com/rishichawda/overthinker/internal/openai"
+	"github.com/rishichawda/overthinker/internal/persona"
+)
+
+const doctorUsageText = `overthink doctor -- check that everything overthink needs is in place
+
+Usage:
+  overthink doctor [flags]
+
+Checks that the Ollama server is reachable and how quickly it answers, lists
+the installed models, confirms that each model named with --thinker supports
+structured JSON output, and reports the terminal's capabilities and the
+configuration in effect. Every check passes, warns or fails; the command
+exits with status 1 if any fails.
+
+Flags:
+  --thinker <spec>    Models to test with a tiny structured-output
+                      generation (e.g. llama3, or llama3,mistral)
+  --timeout <dur>     Time budget for each network check (default 30s)
+  --theme <name>      Color theme, as for a single question
+  --a11y              Screen-reader friendly plain text output
+  --verbose, --debug  Log backend activity to stderr, as for a single question
+  --trace-file <f>    Append all backend traffic to a file
+
+Examples:
+  overthink doctor
+  overthink doctor --thinker llama3
+`
+
+// slowHeartbeat is the heartbeat latency above which the server is reported
+// as slow.
+const slowHeartbeat = 500 * time.Millisecond
+
+// narrowTerminal is the width below which side-by-side output is reported as
+// cramped.
+const narrowTerminal = 80
+
+// runDoctor implements the "doctor" subcommand.
+func runDoctor(args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	thinkerFlag := fs.String("thinker", "", "models to test for structured output")
+	timeoutFlag := fs.Duration("timeout", 30*time.Second, "time budget for each network check")
+	themeFlag := themeFlag(fs)
+	a11yFlag := fs.Bool("a11y", false, "screen-reader friendly plain text output")
+	logging := addLogFlags(fs)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, doctorUsageText) }
+	fs.Parse(args)
+	logging.setup()
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		exit(1)
+	}
+
+	// The theme is checked below rather than applied blindly, so that a
+	// broken theme is reported instead of stopping the diagnosis.
+	themeCheck := checkTheme(*themeFlag)
+	if themeCheck.Status == engine.CheckPass {
+		applyTheme(*themeFlag)
+	}
+
+	aliases, aliasCheck := checkAliases()
+	gen, generationCheck := checkGeneration()
+
+	var checks []engine.Check
+	checks = append(checks, ollamaChecks(backend.OllamaModels(backend.Split(*thinkerFlag)), aliases, gen, *timeoutFlag)...)
+	checks = append(checks, terminalChecks()...)
+	checks = append(checks,
+		configDirCheck(),
+		themeCheck,
+		checkPersona(os.Getenv(envPersona)),
+		aliasCheck,
+		generationCheck,
+		historyCheck(),
+		openaiCheck(),
+	)
+
+	formatter := engine.NewFormatter(os.Stdout)
+	formatter.SetAccessible(*a11yFlag)
+	formatter.PrintChecks(checks)
+
+	for _, c := range checks {
+		if c.Status == engine.CheckFail {
+			exit(1)
+		}
+	}
+}
+
+// ollamaChecks checks the Ollama server, its models, and that each of models
+// answers a structured-output request.
+func ollamaChecks(models []string, aliases map[string]string, gen ollama.Generation, timeout time.Duration) []engine.Check {
+	const group = "Ollama"
+	client := ollama.NewClient("")
+	ctx := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+
+	c, cancel := ctx()
+	latency, err := client.Heartbeat(c)
+	cancel()
+	if err != nil {
+		return []engine.Check{{Group: group, Name: "Server", Status: engine.CheckFail,
+			Detail: fmt.Sprintf("%s: %v", client.Host, err)}}
+	}
+	checks := []engine.Check{{Group: group, Name: "Server", Status: engine.CheckPass,
+		Detail: "reachable at " + client.Host}}
+
+	heartbeat := engine.Check{Group: group, Name: "Heartbeat", Status: engine.CheckPass,
+		Detail: formatDuration(latency)}
+	if latency > slowHeartbeat {
+		heartbeat.Status = engine.CheckWarn
+		heartbeat.Detail += " (slow; analyses may time out)"
+	}
+	checks = append(checks, heartbeat)
+
+	c, cancel = ctx()
+	installed, err := client.InstalledModels(c)
+	cancel()
+	switch {
+	case err != nil:
+		checks = append(checks, engine.Check{Group: group, Name: "Models", Status: engine.CheckFail,
+			Detail: err.Error()})
+	case len(installed) == 0:
+		checks = append(checks, engine.Check{Group: group, Name: "Models", Status: engine.CheckWarn,
+			Detail: "none installed (install one with: ollama pull llama3)"})
+	default:
+		names := make([]string, len(installed))
+		for i, m := range installed {
+			names[i] = fmt.Sprintf("%s (%s)", m.Name, formatBytes(m.Size))
+		}
+		checks = append(checks, engine.Check{Group: group, Name: "Models", Status: engine.CheckPass,
+			Detail: fmt.Sprintf("%d installed: %s", len(installed), strings.Join(names, ", "))})
+	}
+
+	if len(models) == 0 {
+		return append(checks, engine.Check{Group: group, Name: "Structured output", Status: engine.CheckWarn,
+			Detail: "not tested (name a model with --thinker)"})
+	}
+	for _, model := range models {
+		probe := ollama.NewClient(model)
+		probe.Aliases = aliases
+		probe.Generation = gen
+		c, cancel = ctx()
+		start := time.Now()
+		resolved, err := probe.ProbeStructuredOutput(c)
+		cancel()
+		check := engine.Check{Group: group, Name: "Structured output"}
+		switch {
+		case err != nil:
+			check.Status, check.Detail = engine.CheckFail, err.Error()
+		case resolved != model:
+			check.Detail = fmt.Sprintf("%s (as %s) returned valid JSON in %s", model, resolved, formatDuration(time.Since(start)))
+		default:
+			check.Detail = fmt.Sprintf("%s returned valid JSON in %s", model, formatDuration(time.Since(start)))
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// terminalChecks report what the terminal can display.
+func terminalChecks() []engine.Check {
+	const group = "Terminal"
+	checks := make([]engine.Check, 0, 4)
+
+	output := engine.Check{Group: group, Name: "Output", Status: engine.CheckPass, Detail: "a terminal"}
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		output.Detail = "redirected; the TUI and dramatic reveal need a terminal"
+	}
+	checks = append(checks, output)
+
+	color := engine.Check{Group: group, Name: "Color", Status: engine.CheckPass}
+	switch colorDepth() {
+	case engine.ColorTrue:
+		color.Detail = "24-bit"
+	case engine.Color256:
+		color.Detail = "256 colors"
+	default:
+		color.Detail = "16 colors"
+	}
+	if os.Getenv("TERM") == "dumb" {
+		color.Status = engine.CheckWarn
+		color.Detail = "TERM=dumb; escape sequences may show as garbage (try --a11y)"
+	}
+	checks = append(checks, color)
+
+	unicode := engine.Check{Group: group, Name: "Unicode", Status: engine.CheckPass, Detail: "UTF-8 locale"}
+	if locale() == "" {
+		unicode.Detail = "no locale set; assuming UTF-8"
+	}
+	if !unicodeSupported() {
+		unicode.Status = engine.CheckWarn
+		unicode.Detail = "locale is not UTF-8; bars may not display (stats falls back to ASCII)"
+	}
+	checks = append(checks, unicode)
+
+	width := terminalWidth()
+	size := engine.Check{Group: group, Name: "Width", Status: engine.CheckPass, Detail: fmt.Sprintf("%d columns", width)}
+	if width < narrowTerminal {
+		size.Status = engine.CheckWarn
+		size.Detail += "; compare columns will be cramped (try --layout panels)"
+	}
+	return append(checks, size)
+}
+
+// configGroup is the group of the configuration checks.
+const configGroup = "Configuration"
+
+// configDirCheck reports overthink's configuration directory.
+func configDirCheck() engine.Check {
+	check := engine.Check{Group: configGroup, Name: "Directory", Status: engine.CheckPass}
+	dir := configDir("")
+	if dir == "" {
+		check.Status, check.Detail = engine.CheckWarn, "cannot be determined; user themes, personas and aliases are unavailable"
+		return check
+	}
+	check.Detail = dir
+	return check
+}
+
+// checkTheme reports whether the named theme loads.
+func checkTheme(name string) engine.Check {
+	check := engine.Check{Group: configGroup, Name: "Theme", Status: engine.CheckPass}
+	if name == "" {
+		check.Detail = engine.DefaultTheme.Name
+		return check
+	}
+	if _, err := loadTheme(name); err != nil {
+		check.Status, check.Detail = engine.CheckFail, err.Error()
+		return check
+	}
+	check.Detail = name
+	return check
+}
+
+// checkPersona reports whether the named persona loads.
+func checkPersona(name string) engine.Check {
+	check := engine.Check{Group: configGroup, Name: "Persona", Status: engine.CheckPass}
+	if name == "" {
+		check.Detail = persona.Default
+		return check
+	}
+	if _, err := loadPersona(name); err != nil {
+		check.Status, check.Detail = engine.CheckFail, err.Error()
+		return check
+	}
+	check.Detail = name
+	return check
+}
+
+// checkAliases loads the model aliases and reports them.
+func checkAliases() (map[string]string, engine.Check) {
+	check := engine.Check{Group: configGroup, Name: "Aliases", Status: engine.CheckPass}
+	aliases, err := loadAliases()
+	switch {
+	case err != nil:
+		check.Status, check.Detail = engine.CheckFail, err.Error()
+	case len(aliases) == 0:
+		check.Detail = "none"
+	default:
+		pairs := make([]string, 0, len(aliases))
+		for alias, model := range aliases {
+			pairs = append(pairs, alias+"="+model)
+		}
+		slices.Sort(pairs)
+		check.Detail = strings.Join(pairs, ", ")
+	}
+	return aliases, check
+}
+
+// checkGeneration resolves the generation options set in the environment
+// and in generation.json.
+func checkGeneration() (ollama.Generation, engine.Check) {
+	check := engine.Check{Group: configGroup, Name: "Generation", Status: engine.CheckPass}
+	gen, err := addGenerationFlags(flag.NewFlagSet("", flag.ContinueOnError)).parse()
+	if err != nil {
+		check.Status, check.Detail = engine.CheckFail, err.Error()
+		return gen, check
+	}
+
+	var set []string
+	if gen.Temperature != nil {
+		set = append(set, fmt.Sprintf("temperature %g", *gen.Temperature))
+	}
+	if gen.Seed != nil {
+		set = append(set, fmt.Sprintf("seed %d", *gen.Seed))
+	}
+	if gen.TopP != nil {
+		set = append(set, fmt.Sprintf("top-p %g", *gen.TopP))
+	}
+	if gen.NumCtx != nil {
+		set = append(set, fmt.Sprintf("num-ctx %d", *gen.NumCtx))
+	}
+	if gen.NumPredict != nil {
+		set = append(set, fmt.Sprintf("num-predict %d", *gen.NumPredict))
+	}
+	if gen.KeepAlive != nil {
+		set = append(set, "keep-alive "+gen.KeepAlive.String())
+	}
+	check.Detail = "model defaults"
+	if len(set) > 0 {
+		check.Detail = strings.Join(set, ", ")
+	}
+	return gen, check
+}
+
+// historyCheck reports where the history log is kept.
+func historyCheck() engine.Check {
+	check := engine.Check{Group: configGroup, Name: "History", Status: engine.CheckPass}
+	path := history.DefaultPath()
+	if path == "" {
+		check.Detail = fmt.Sprintf("off (%s=%s)", history.EnvPath, os.Getenv(history.EnvPath))
+		return check
+	}
+	check.Detail = path
+	return check
+}
+
+// openaiCheck reports the OpenAI-compatible server used by openai: thinkers.
+func openaiCheck() engine.Check {
+	check := engine.Check{Group: configGroup, Name: "OpenAI server", Status: engine.CheckPass}
+	url := os.Getenv(openai.EnvBaseURL)
+	if url == "" {
+		url = openai.DefaultBaseURL + " (default)"
+	}
+	check.Detail = url
+	if os.Getenv(openai.EnvAPIKey) != "" {
+		check.Detail += ", API key set"
+	}
+	return check
+}
+
+// formatDuration rounds a short duration for display.
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
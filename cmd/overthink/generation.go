@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rishichawda/overthinker/internal/ollama"
+)
+
+// envSeed names the environment variable --seed defaults to, wherever it
+// appears.
+const envSeed = "OVERTHINK_SEED"
+
+// generationFile is the name of the generation options file in overthink's
+// configuration directory.
+const generationFile = "generation.json"
+
+// generationKeys are the options generation.json may set, named after their
+// flags.
+var generationKeys = []string{"temperature", "seed", "top-p", "num-ctx", "num-predict", "keep-alive"}
+
+// generationConfig reads generation.json from overthink's configuration
+// directory: a JSON object of generation options keyed by flag name, e.g.
+// {"seed": 42, "keep-alive": "10m"}. Values are returned as they would be
+// written on the command line. A missing file sets nothing.
+var generationConfig = sync.OnceValues(func() (map[string]string, error) {
+	return loadGenerationConfig(configDir(""))
+})
+
+// loadGenerationConfig reads generation.json from dir.
+func loadGenerationConfig(dir string) (map[string]string, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	path := filepath.Join(dir, generationFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	config := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if !slices.Contains(generationKeys, key) {
+			return nil, fmt.Errorf("%s: unknown option %q (want %s)", path, key, strings.Join(generationKeys, ", "))
+		}
+		var s string
+		if json.Unmarshal(value, &s) != nil {
+			s = string(value)
+		}
+		config[key] = s
+	}
+	return config, nil
+}
+
+// generationDefault returns the default of the generation flag name: the
+// environment variable env when set, otherwise the value in generation.json.
+func generationDefault(name, env string) string {
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	config, _ := generationConfig()
+	return config[name]
+}
+
+// addSeedFlag registers --seed on fs, defaulting to $OVERTHINK_SEED or the
+// seed in generation.json.
+func addSeedFlag(fs *flag.FlagSet, usage string) *string {
+	return fs.String("seed", generationDefault("seed", envSeed), usage)
+}
+
+// parseSeed parses the value of a --seed flag, returning nil when it is empty.
+func parseSeed(s string) (*int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	seed, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed %q (want an integer)", s)
+	}
+	return &seed, nil
+}
+
+// generationFlags are the flags holding the Ollama generation options. Each
+// defaults to an OVERTHINK_* environment variable and then to generation.json,
+// so the options can be set for good. A flag is set when it, its variable or
+// the file gives it a value, and a set flag is sent even when it is zero.
+type generationFlags struct {
+	temperature *string
+	seed        *string
+	topP        *string
+	numCtx      *string
+	numPredict  *string
+	keepAlive   *string
+}
+
+// addGenerationFlags registers the generation flags on fs.
+func addGenerationFlags(fs *flag.FlagSet) *generationFlags {
+	return &generationFlags{
+		temperature: fs.String("temperature", generationDefault("temperature", "OVERTHINK_TEMPERATURE"), "sampling temperature for Ollama models"),
+		seed:        addSeedFlag(fs, "seed for reproducible output from Ollama models and the local engine"),
+		topP:        fs.String("top-p", generationDefault("top-p", "OVERTHINK_TOP_P"), "nucleus sampling threshold for Ollama models"),
+		numCtx:      fs.String("num-ctx", generationDefault("num-ctx", "OVERTHINK_NUM_CTX"), "context window of Ollama models, in tokens"),
+		numPredict:  fs.String("num-predict", generationDefault("num-predict", "OVERTHINK_NUM_PREDICT"), "most tokens an Ollama model may generate"),
+		keepAlive:   fs.String("keep-alive", generationDefault("keep-alive", "OVERTHINK_KEEP_ALIVE"), "how long Ollama keeps the model loaded, e.g. 10m"),
+	}
+}
+
+// mustParse returns the generation options the flags describe, exiting with
+// an error if any is invalid. Unset flags leave the model's defaults.
+func (g *generationFlags) mustParse() ollama.Generation {
+	gen, err := g.parse()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		os.Exit(1)
+	}
+	return gen
+}
+
+func (g *generationFlags) parse() (ollama.Generation, error) {
+	var gen ollama.Generation
+	if _, err := generationConfig(); err != nil {
+		return gen, err
+	}
+	if *g.temperature != "" {
+		t, err := strconv.ParseFloat(*g.temperature, 64)
+		if err != nil || t < 0 {
+			return gen, fmt.Errorf("invalid temperature %q (want a number of at least 0)", *g.temperature)
+		}
+		gen.Temperature = &t
+	}
+	seed, err := parseSeed(*g.seed)
+	if err != nil {
+		return gen, err
+	}
+	gen.Seed = seed
+	if *g.topP != "" {
+		p, err := strconv.ParseFloat(*g.topP, 64)
+		if err != nil || p <= 0 || p > 1 {
+			return gen, fmt.Errorf("invalid top-p %q (want a number above 0, up to 1)", *g.topP)
+		}
+		gen.TopP = &p
+	}
+	if *g.numCtx != "" {
+		n, err := strconv.Atoi(*g.numCtx)
+		if err != nil || n < 1 {
+			return gen, fmt.Errorf("invalid num-ctx %q (want a positive number of tokens)", *g.numCtx)
+		}
+		gen.NumCtx = &n
+	}
+	if *g.numPredict != "" {
+		n, err := strconv.Atoi(*g.numPredict)
+		if err != nil || n < 1 {
+			return gen, fmt.Errorf("invalid num-predict %q (want a positive number of tokens)", *g.numPredict)
+		}
+		gen.NumPredict = &n
+	}
+	if *g.keepAlive != "" {
+		d, err := time.ParseDuration(*g.keepAlive)
+		if err != nil {
+			return gen, fmt.Errorf("invalid keep-alive %q (want a duration such as 10m, 0 to unload at once, or -1s to keep the model loaded)", *g.keepAlive)
+		}
+		gen.KeepAlive = &d
+	}
+	return gen, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rishichawda/overthinker/internal/backend"
+)
+
+func TestLoadGenerationConfig(t *testing.T) {
+	dir := t.TempDir()
+	if config, err := loadGenerationConfig(dir); err != nil || config != nil {
+		t.Fatalf("missing file: %v, %v", config, err)
+	}
+
+	path := filepath.Join(dir, generationFile)
+	os.WriteFile(path, []byte(`{"seed": 0, "temperature": 0.7, "keep-alive": "10m"}`), 0o644)
+	config, err := loadGenerationConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"seed": "0", "temperature": "0.7", "keep-alive": "10m"}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("config = %v, want %v", config, want)
+	}
+
+	os.WriteFile(path, []byte(`{"sead": 1}`), 0o644)
+	if _, err := loadGenerationConfig(dir); err == nil {
+		t.Error("unknown option accepted")
+	}
+}
+
+// TestSeedZeroIsReproducible runs the built-in engine twice with --seed 0, as
+// a thinker of its own and as the chain's fallback, and expects the same
+// report both times.
+func TestSeedZeroIsReproducible(t *testing.T) {
+	run := func(spec string) string {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		gen := addGenerationFlags(fs)
+		if err := fs.Parse([]string{"--seed", "0"}); err != nil {
+			t.Fatal(err)
+		}
+		g, err := gen.parse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		thinker, err := backend.NewChain([]string{spec}, backend.Options{Generation: g})
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := thinker.Analyze("Should I reply all?")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	for _, spec := range []string{backend.Local, "local:0"} {
+		if a, b := run(spec), run(spec); a != b {
+			t.Errorf("%s: two runs with --seed 0 differ:\n%s\n%s", spec, a, b)
+		}
+	}
+}
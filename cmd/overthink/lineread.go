@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// historyFileName is the REPL history file, kept in the user's home directory.
+const historyFileName = ".overthink_history"
+
+// maxHistory bounds how many history entries are loaded and kept.
+const maxHistory = 500
+
+// historySlack is how far the history file may grow past maxHistory before
+// it is trimmed, so that it is rewritten once every historySlack entries
+// rather than each time.
+const historySlack = 100
+
+// lineReader reads one line of input at a time.
+type lineReader interface {
+	// ReadLine returns the next line without its newline, or io.EOF.
+	ReadLine() (string, error)
+}
+
+// newLineReader returns a line editor with history when stdin is a terminal,
+// and a plain line scanner otherwise (e.g. when input is piped).
+func newLineReader(prompt string) lineReader {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return &plainReader{scanner: bufio.NewScanner(os.Stdin)}
+	}
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, prompt)
+	t.History = loadHistory()
+	if w, h, err := term.GetSize(fd); err == nil && w > 0 && h > 0 {
+		t.SetSize(w, h)
+	}
+	return &terminalReader{fd: fd, term: t}
+}
+
+// terminalReader edits lines in raw mode using x/term. Raw mode is held only
+// while a line is being read, so ordinary output is unaffected.
+type terminalReader struct {
+	fd   int
+	term *term.Terminal
+}
+
+func (r *terminalReader) ReadLine() (string, error) {
+	state, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(r.fd, state)
+
+	line, err := r.term.ReadLine()
+	if errors.Is(err, term.ErrPasteIndicator) {
+		err = nil
+	}
+	return line, err
+}
+
+// plainReader reads newline-separated input without editing.
+type plainReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *plainReader) ReadLine() (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// fileHistory is a term.History that also appends every entry to a file, so
+// questions can be recalled across sessions.
+type fileHistory struct {
+	path    string
+	entries []string // oldest first
+	lines   int      // entries in the file, which may exceed len(entries)
+}
+
+// loadHistory reads the history file from the home directory. Any failure
+// yields an empty, in-memory-only history.
+func loadHistory() *fileHistory {
+	h := &fileHistory{}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return h
+	}
+	h.path = filepath.Join(home, historyFileName)
+	if data, err := os.ReadFile(h.path); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if line != "" {
+				h.entries = append(h.entries, line)
+			}
+		}
+	}
+	h.lines = len(h.entries)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[len(h.entries)-maxHistory:]
+	}
+	return h
+}
+
+func (h *fileHistory) Add(entry string) {
+	if entry == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[1:]
+	}
+	if h.path == "" {
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	f.WriteString(entry + "\n")
+	f.Close()
+	if h.lines++; h.lines > maxHistory+historySlack {
+		h.trim()
+	}
+}
+
+// trim rewrites the history file with only the entries kept in memory. A
+// failure leaves the file as it was, to be trimmed on a later Add.
+func (h *fileHistory) trim() {
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), ".overthink_history-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(strings.Join(h.entries, "\n") + "\n")
+	if err := errors.Join(err, tmp.Chmod(0o600), tmp.Close()); err != nil {
+		return
+	}
+	if os.Rename(tmp.Name(), h.path) == nil {
+		h.lines = len(h.entries)
+	}
+}
+
+func (h *fileHistory) Len() int { return len(h.entries) }
+
+func (h *fileHistory) At(idx int) string { return h.entries[len(h.entries)-1-idx] }
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileHistoryIsTrimmed(t *testing.T) {
+	h := &fileHistory{path: filepath.Join(t.TempDir(), historyFileName)}
+	total := maxHistory + historySlack + 1
+	for i := range total {
+		h.Add(fmt.Sprintf("question %d", i))
+	}
+
+	data, err := os.ReadFile(h.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != maxHistory {
+		t.Fatalf("history file has %d lines, want %d", len(lines), maxHistory)
+	}
+	if want := fmt.Sprintf("question %d", total-1); lines[len(lines)-1] != want {
+		t.Errorf("last line = %q, want %q", lines[len(lines)-1], want)
+	}
+	if h.Len() != maxHistory || h.At(0) != lines[len(lines)-1] {
+		t.Errorf("in-memory history diverged from the file")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/rishichawda/overthinker/internal/trace"
+)
+
+// tracer traces backend traffic when --trace-file is given.
+var tracer *trace.Transport
+
+// logFlags are the flags controlling diagnostic output.
+type logFlags struct {
+	verbose   *bool
+	debug     *bool
+	traceFile *string
+}
+
+// addLogFlags registers --verbose, --debug and --trace-file on fs.
+func addLogFlags(fs *flag.FlagSet) *logFlags {
+	return &logFlags{
+		verbose:   fs.Bool("verbose", false, "log backend activity to stderr"),
+		debug:     fs.Bool("debug", false, "log backend activity in detail, including raw model output"),
+		traceFile: fs.String("trace-file", "", "write every request to and response from the backends to a file"),
+	}
+}
+
+// setup configures logging as the flags ask: slog output on stderr at info
+// level with --verbose and debug level with --debug, and nothing otherwise.
+// With --trace-file every HTTP exchange is appended to the file. It exits
+// with an error if the trace file cannot be opened.
+func (l *logFlags) setup() {
+	var handler slog.Handler = slog.DiscardHandler
+	switch {
+	case *l.debug:
+		handler = slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+	case *l.verbose:
+		handler = slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})
+	}
+	slog.SetDefault(slog.New(handler))
+
+	if *l.traceFile == "" {
+		return
+	}
+	// The file is left for the process to close on exit, since responses are
+	// traced until the very end of the run.
+	f, err := os.OpenFile(*l.traceFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		os.Exit(1)
+	}
+	tracer = &trace.Transport{Base: http.DefaultTransport, W: f}
+	http.DefaultTransport = tracer
+	slog.Info("tracing backend traffic", "file", *l.traceFile)
+}
+
+// flushTrace writes out the exchanges still in progress to the trace file,
+// if there is one.
+func flushTrace() {
+	if tracer != nil {
+		tracer.Flush()
+	}
+}
+
+// exit ends the run with code, keeping whatever the trace file has yet to
+// record. Commands that talk to the backends exit through it rather than
+// os.Exit.
+func exit(code int) {
+	flushTrace()
+	os.Exit(code)
+}
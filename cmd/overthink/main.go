@@ -6,80 +6,409 @@
 //
 //	overthink "Should I text my ex?"
 //	overthink --thinker llama3 "Should I quit my job?"
+//	overthink compare --thinker llama3,mistral,local "Should I quit my job?"
+//	overthink batch --thinker llama3 questions.txt > results.ndjson
+//	overthink commit
+//	overthink stats
+//	overthink doctor
+//	git log -1 --format=%s | overthink --json
+//
+// When no question is given on the command line and stdin is not a terminal,
+// the question is read from stdin.
 //
 // If --thinker is provided, the question is sent to a locally running Ollama
-// server via the HTTP API. If Ollama is unavailable or fails, the built-in
-// local engine takes over.
+// server via the HTTP API, or to an ensemble of thinkers with
+// --thinker ensemble:llama3,mistral,local. A comma-separated list such as
+// --thinker llama3,mistral,local forms a fallback chain: each thinker is
+// tried in turn, with retries for transient failures, and the built-in local
+// engine always closes the chain.
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
+	"golang.org/x/term"
+
+	"github.com/rishichawda/overthinker/internal/backend"
+	"github.com/rishichawda/overthinker/internal/card"
+	"github.com/rishichawda/overthinker/internal/chain"
 	"github.com/rishichawda/overthinker/internal/engine"
 	"github.com/rishichawda/overthinker/internal/local"
-	"github.com/rishichawda/overthinker/internal/ollama"
+	"github.com/rishichawda/overthinker/internal/tui"
 )
 
 const usageText = `overthink -- a dramatic overanalysis engine
 
 Usage:
   overthink [flags] "<your question>"
+  echo "<your question>" | overthink [flags]
+  overthink compare --thinker <a,b,...> "<your question>"
+  overthink batch [flags] <questions.txt | questions.csv | ->
+  overthink commit [flags]
+  overthink stats [flags]
+  overthink doctor [flags]
 
 Flags:
   --thinker <model>   Use a local Ollama model (e.g. llama3, mistral)
                       Falls back to built-in engine if Ollama is unavailable.
+                      A list (llama3,mistral,local) is tried in order;
+                      ensemble:<a>,<b>,... merges several thinkers' results;
+                      hybrid:<model> computes the numbers locally and lets
+                      the model write the prose around them;
+                      openai:<model> uses an OpenAI-compatible server
+                      (llama.cpp, LM Studio, vLLM) at $OPENAI_BASE_URL;
+                      local:<seed> runs the built-in engine with a fixed seed.
+  --timeout <dur>     Time budget for each thinker attempt (default 2m)
+  --retries <n>       Extra attempts after a transient failure (default 2)
+  -i, --interactive   Open a REPL: one question per line, with history and
+                      /thinker, /seed and /output commands
+  --tui               Full-screen report with animated bars, foldable sections
+                      and keys to regenerate, copy as Markdown or switch thinker
+  --dramatic          Reveal the report theatrically: typed title, a pause
+                      before the risk index, filling bars (terminals only)
+  --dramatic-speed <x>
+                      Speed up (2) or slow down (0.5) the dramatic reveal
+  --chart <style>     bar (default), pie (probabilities as a donut), gauge
+                      (risk index as a dial) or radar (risk by theme)
+  --theme <name>      Color theme: default, monochrome, solarized,
+                      high-contrast, colorblind-safe, retro-green-phosphor,
+                      a JSON theme file, or a file in the themes directory
+                      (default $OVERTHINK_THEME)
+  --follow-up         After the report, keep asking follow-up questions;
+                      the Ollama model remembers its previous analysis
+  --session <file>    Save the conversation to a file and resume it later
+  --context-file <f>  Send the file to the model as background for the
+                      question; the built-in engine mines it for risk
+                      keywords
+  --json              Print the analysis as JSON instead of the report
+  --intensity <1-5>   Drama from 1 (mildly concerned) to 5 (full Greek
+                      tragedy): risk range, number of outcomes and
+                      citations, tone, and the model's temperature
+                      (default 3)
+  --persona <name>    Narrator: overthink (default), noir-detective,
+                      victorian-physician, corporate-consultant,
+                      sports-commentator, anxious-intern, a JSON persona
+                      file, or a file in the personas directory
+                      (default $OVERTHINK_PERSONA)
+  --pull              Pull missing Ollama models first, with a progress bar,
+                      instead of falling back to the built-in engine
+  --seed <n>          Reproducible output: seeds Ollama sampling and the
+                      built-in engine ($OVERTHINK_SEED)
+  --temperature <t>   Sampling temperature for Ollama models; overrides
+                      the one --intensity picks ($OVERTHINK_TEMPERATURE)
+  --top-p <p>         Nucleus sampling threshold, 0-1 ($OVERTHINK_TOP_P)
+  --num-ctx <n>       Ollama context window in tokens ($OVERTHINK_NUM_CTX)
+  --num-predict <n>   Most tokens an Ollama model may generate
+                      ($OVERTHINK_NUM_PREDICT)
+  --keep-alive <dur>  How long Ollama keeps the model loaded afterwards;
+                      0 unloads it at once, negative keeps it loaded
+                      ($OVERTHINK_KEEP_ALIVE)
+                      Each generation option falls back to its OVERTHINK_*
+                      variable, then to generation.json in the config
+                      directory; any value given, 0 included, is sent
+  --a11y              Screen-reader friendly output: plain labelled text,
+                      risk levels in words, no bars, colors or animation
+  --verbose           Log backend activity to stderr: host, model, preflight,
+                      timing and fallbacks
+  --debug             Log in detail, adding request options, chunk counts
+                      and raw model output that failed to parse
+  --trace-file <f>    Append every request to and response from the
+                      backends to a file, for bug reports
+  --export <file>     Also save a shareable 1200x630 card (.svg or .png)
 
 Examples:
   overthink "Should I text my ex?"
   overthink "Is it too late to start coding?"
   overthink --thinker llama3 "Should I quit my job?"
+  overthink --thinker llama3,mistral,local "Should I quit my job?"
+  overthink --thinker ensemble:llama3,mistral,local "Should I quit my job?"
+  overthink --thinker hybrid:llama3 "Should I quit my job?"
+  overthink --thinker llama3 --pull "Should I quit my job?"
+  overthink --thinker llama3 --seed 42 --temperature 0.8 "Should I quit my job?"
+  overthink --thinker openai:qwen2.5-7b "Should I quit my job?"
+  overthink compare --thinker llama3,mistral,local "Should I quit my job?"
+  overthink batch --thinker llama3 --out-dir results questions.txt
+  overthink commit --install-hook
+  overthink stats --last 100
+  overthink doctor --thinker llama3
+  overthink --thinker llama3 --follow-up --session spiral.json "Should I text her?"
+  overthink --session spiral.json --follow-up
+  overthink -i --thinker llama3
+  overthink --tui "Should I text my ex?"
+  overthink --dramatic "Should I text my ex?"
+  overthink --chart radar "Should I lend my ex money?"
+  overthink --theme solarized "Should I text my ex?"
+  overthink --a11y "Should I text my ex?"
+  overthink --intensity 5 "Should I reply-all?"
+  overthink --persona noir-detective --thinker llama3 "Should I call her?"
+  overthink --export card.png "Should I text my ex?"
+  echo "Should I refactor?" | overthink --json
+  overthink --context-file design.md --thinker llama3 "Should I refactor?"
+  overthink --thinker llama3 --debug --trace-file trace.log "Should I refactor?"
 
-If no question is provided, this message is printed and the program exits.
+If no question is provided on the command line or on stdin, this message is
+printed and the program exits.
 `
 
 func main() {
-	thinkerFlag := flag.String("thinker", "", "Ollama model name to use for analysis")
+	// Exchanges abandoned by a timed-out thinker may still be in flight.
+	defer flushTrace()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "compare":
+			runCompare(os.Args[2:])
+			return
+		case "batch":
+			runBatch(os.Args[2:])
+			return
+		case "commit":
+			runCommit(os.Args[2:])
+			return
+		case "stats":
+			runStats(os.Args[2:])
+			return
+		case "doctor":
+			runDoctor(os.Args[2:])
+			return
+		}
+	}
+
+	thinkerFlag := flag.String("thinker", "", "Ollama model name or thinker spec to use for analysis")
+	timeoutFlag := flag.Duration("timeout", chain.DefaultTimeout, "time budget for each thinker attempt")
+	retriesFlag := flag.Int("retries", chain.DefaultRetries, "extra attempts after a transient failure")
+	followUpFlag := flag.Bool("follow-up", false, "ask follow-up questions after the report")
+	sessionFlag := flag.String("session", "", "file to save and resume the conversation")
+	tuiFlag := flag.Bool("tui", false, "show the report in a full-screen terminal UI")
+	dramaticFlag := flag.Bool("dramatic", false, "reveal the report theatrically")
+	dramaticSpeedFlag := flag.Float64("dramatic-speed", 1, "speed factor for the dramatic reveal")
+	contextFileFlag := flag.String("context-file", "", "file sent along with the question as background")
+	jsonFlag := flag.Bool("json", false, "print the analysis as JSON")
+	exportFlag := flag.String("export", "", "also save the report as an SVG or PNG card")
+	chartFlag := flag.String("chart", string(engine.ChartBar), "bar, pie, gauge or radar")
+	themeFlag := themeFlag(flag.CommandLine)
+	a11yFlag := flag.Bool("a11y", false, "screen-reader friendly plain text output")
+	intensityFlag := flag.String("intensity", "3", "drama from 1 (mildly concerned) to 5 (full Greek tragedy)")
+	personaFlag := personaFlag(flag.CommandLine)
+	generation := addGenerationFlags(flag.CommandLine)
+	pullFlag := flag.Bool("pull", false, "pull missing Ollama models before the analysis")
+	logging := addLogFlags(flag.CommandLine)
+	var interactive bool
+	flag.BoolVar(&interactive, "i", false, "open an interactive session")
+	flag.BoolVar(&interactive, "interactive", false, "open an interactive session")
 
 	flag.Usage = func() { fmt.Fprint(os.Stderr, usageText) }
 	flag.Parse()
+	logging.setup()
+	applyTheme(*themeFlag)
 
-	args := flag.Args()
-	if len(args) == 0 {
-		flag.Usage()
-		os.Exit(1)
+	chart, err := parseChart(*chartFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		exit(1)
 	}
+	formatter := engine.NewFormatter(os.Stdout)
+	formatter.SetChart(chart)
+	formatter.SetASCII(!unicodeSupported())
+	formatter.SetAccessible(*a11yFlag)
+	formatter.SetDramatic(dramaticPacing(*dramaticFlag, *a11yFlag, os.Stdout, *dramaticSpeedFlag))
 
-	question := strings.TrimSpace(strings.Join(args, " "))
-	if question == "" {
+	if *a11yFlag && *tuiFlag {
+		fmt.Fprintln(os.Stderr, "overthink: --a11y cannot be combined with --tui")
+		exit(1)
+	}
+	if (*logging.verbose || *logging.debug) && *tuiFlag {
+		fmt.Fprintln(os.Stderr, "overthink: --verbose and --debug cannot be combined with --tui (use --trace-file)")
+		exit(1)
+	}
+
+	if *jsonFlag && (interactive || *tuiFlag || *followUpFlag || *sessionFlag != "") {
+		fmt.Fprintln(os.Stderr, "overthink: --json cannot be combined with -i, --tui, --follow-up or --session")
+		exit(1)
+	}
+
+	if *retriesFlag < 0 {
+		fmt.Fprintln(os.Stderr, "overthink: --retries cannot be negative")
+		exit(1)
+	}
+
+	if *exportFlag != "" && !card.Supported(*exportFlag) {
+		fmt.Fprintf(os.Stderr, "overthink: %v: %s\n", card.ErrFormat, *exportFlag)
+		exit(1)
+	}
+
+	opts := backend.Options{
+		Timeout:    *timeoutFlag,
+		Retries:    *retriesFlag,
+		Intensity:  mustIntensity(*intensityFlag),
+		Persona:    mustPersona(*personaFlag),
+		Generation: generation.mustParse(),
+		Aliases:    mustAliases(),
+	}
+	if opts.Persona != nil {
+		formatter.SetHeadings(opts.Persona.Headings)
+	}
+	if *contextFileFlag != "" {
+		data, err := os.ReadFile(*contextFileFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			exit(1)
+		}
+		opts.Context = strings.TrimSpace(string(data))
+	}
+
+	if interactive {
+		if *pullFlag {
+			pullModels(backend.Split(*thinkerFlag), opts.Aliases, *a11yFlag)
+		}
+		runREPL(backend.Split(*thinkerFlag), opts, formatter)
+		return
+	}
+
+	question := strings.TrimSpace(strings.Join(flag.Args(), " "))
+	resuming := *sessionFlag != "" && *followUpFlag
+	if question == "" && !resuming && !term.IsTerminal(int(os.Stdin.Fd())) {
+		q, err := readQuestion(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: reading question from stdin: %v\n", err)
+			exit(1)
+		}
+		question = q
+	}
+	if question == "" && !resuming {
 		flag.Usage()
-		os.Exit(1)
+		exit(1)
+	}
+	if *pullFlag {
+		pullModels(backend.Split(*thinkerFlag), opts.Aliases, *a11yFlag)
 	}
 
-	formatter := engine.NewFormatter(os.Stdout)
+	if *tuiFlag {
+		err := tui.Run(tui.Options{
+			Question:   question,
+			Specs:      backend.Split(*thinkerFlag),
+			Timeout:    opts.Timeout,
+			Retries:    opts.Retries,
+			Context:    opts.Context,
+			Intensity:  opts.Intensity,
+			Persona:    opts.Persona,
+			Generation: opts.Generation,
+			Aliases:    opts.Aliases,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			exit(1)
+		}
+		return
+	}
 
-	if *thinkerFlag != "" {
-		runWithOllama(question, *thinkerFlag, formatter)
+	if *followUpFlag || *sessionFlag != "" {
+		runConversation(question, *thinkerFlag, *sessionFlag, *followUpFlag, opts, os.Stdin, formatter)
 		return
 	}
 
-	result, _ := local.New().Analyze(question)
-	formatter.Print(result)
+	var result *engine.AnalysisResult
+	thinker := backend.Local
+	if *thinkerFlag != "" {
+		result = analyzeWithThinkers(question, backend.Split(*thinkerFlag), opts)
+		thinker = result.Attempts[len(result.Attempts)-1].Thinker
+	} else {
+		result, _ = opts.LocalEngine(nil).Analyze(question)
+	}
+	recordHistory(thinker, result)
+
+	if *jsonFlag {
+		printJSON(result)
+	} else {
+		addRiskProfile(result, question, opts.Context)
+		if *thinkerFlag != "" {
+			formatter.PrintAttempts(result.Attempts)
+			formatter.PrintModelHeader(thinker)
+		}
+		formatter.Print(result)
+	}
+
+	if *exportFlag != "" {
+		if err := card.Export(*exportFlag, result); err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			exit(1)
+		}
+	}
 }
 
-// runWithOllama queries the Ollama model and renders the result with full
-// formatting. On any error it warns and falls back to the local engine.
-func runWithOllama(question, model string, formatter *engine.Formatter) {
-	result, err := ollama.NewClient(model).Analyze(question)
+// analyzeWithThinkers tries each thinker in specs in turn, falling back to
+// the local engine. The result carries the trail of attempts.
+func analyzeWithThinkers(question string, specs []string, opts backend.Options) *engine.AnalysisResult {
+	c, err := backend.NewChain(specs, opts)
 	if err != nil {
-		formatter.PrintWarning(fmt.Sprintf("%v", err))
-		result, _ = local.New().Analyze(question)
-		formatter.Print(result)
-		return
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		exit(1)
 	}
 
-	formatter.PrintModelHeader(model)
-	formatter.Print(result)
+	result, err := c.Analyze(question)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		exit(1)
+	}
+	return result
+}
+
+// parseChart validates a --chart value.
+func parseChart(name string) (engine.Chart, error) {
+	names := make([]string, len(engine.Charts))
+	for i, c := range engine.Charts {
+		if string(c) == name {
+			return c, nil
+		}
+		names[i] = string(c)
+	}
+	return "", fmt.Errorf("unknown chart %q (want %s)", name, strings.Join(names, ", "))
+}
+
+// mustIntensity parses an --intensity value, exiting with an error if it is
+// out of range.
+func mustIntensity(s string) engine.Intensity {
+	intensity, err := engine.ParseIntensity(s)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		exit(1)
+	}
+	return intensity
+}
+
+// addRiskProfile gives result the local engine's keyword-based risk profile
+// when its thinker did not supply one, so every result can be drawn as a
+// radar chart.
+func addRiskProfile(result *engine.AnalysisResult, question, context string) {
+	if len(result.RiskProfile) == 0 {
+		result.RiskProfile = local.RiskProfile(question + "\n" + context)
+	}
+}
+
+// maxStdinQuestion bounds how much of stdin is read as the question.
+const maxStdinQuestion = 64 << 10
+
+// readQuestion reads a question piped on stdin. Line breaks and runs of
+// whitespace collapse to single spaces, so multi-line input such as a full
+// commit message becomes one question.
+func readQuestion(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxStdinQuestion))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(string(data)), " "), nil
+}
+
+// printJSON writes result to stdout as indented JSON.
+func printJSON(result *engine.AnalysisResult) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		exit(1)
+	}
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rishichawda/overthinker/internal/persona"
+)
+
+// envPersona names the environment variable holding the persona used when
+// no --persona flag is given.
+const envPersona = "OVERTHINK_PERSONA"
+
+// personaFlag registers the --persona flag on fs, defaulting to
+// $OVERTHINK_PERSONA.
+func personaFlag(fs *flag.FlagSet) *string {
+	return fs.String("persona", os.Getenv(envPersona), "narrator: a built-in name or a JSON persona file")
+}
+
+// mustPersona loads the named persona, exiting with an error if it cannot
+// be loaded. An empty name returns nil, which keeps the standard narrator.
+func mustPersona(name string) *persona.Persona {
+	if name == "" {
+		return nil
+	}
+	p, err := loadPersona(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		os.Exit(1)
+	}
+	return p
+}
+
+// loadPersona resolves a persona name: a path to a JSON file, a built-in
+// persona, or <name>.json in the user's personas directory.
+func loadPersona(name string) (*persona.Persona, error) {
+	if strings.ContainsRune(name, filepath.Separator) || strings.HasSuffix(name, ".json") {
+		return persona.Load(name)
+	}
+	if p, ok := persona.Named(name); ok {
+		return p, nil
+	}
+	if dir := configDir("personas"); dir != "" {
+		path := filepath.Join(dir, name+".json")
+		if _, err := os.Stat(path); err == nil {
+			return persona.Load(path)
+		}
+	}
+	return nil, fmt.Errorf("unknown persona %q (want %s, or a JSON persona file)",
+		name, strings.Join(persona.Names(), ", "))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/rishichawda/overthinker/internal/backend"
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/ollama"
+)
+
+// pullBarWidth is the width of the download progress bar.
+const pullBarWidth = 30
+
+// pullModels makes sure every Ollama model among specs is installed, pulling
+// the missing ones with progress shown on stderr. Failures are reported and
+// otherwise ignored: the thinker chain falls back as it would without --pull.
+func pullModels(specs []string, aliases map[string]string, plain bool) {
+	plain = plain || !term.IsTerminal(int(os.Stderr.Fd()))
+	pullModelsFrom(os.Stderr, ollama.OllamaHost, specs, aliases, plain)
+}
+
+// pullModelsFrom is pullModels against the Ollama server at host, with the
+// progress written to w.
+func pullModelsFrom(w io.Writer, host string, specs []string, aliases map[string]string, plain bool) {
+	for _, model := range backend.OllamaModels(specs) {
+		d := &pullDisplay{w: w, model: model, plain: plain}
+		client := ollama.NewClient(model)
+		client.Host = host
+		client.Aliases = aliases
+		err := client.EnsureModel(context.Background(), d.update)
+		d.finish(err)
+		if errors.Is(err, ollama.ErrOllamaNotFound) {
+			return
+		}
+	}
+}
+
+// pullDisplay shows the progress of one model's pull. Downloads are drawn as
+// a bar redrawn in place; every other step is a line of its own. A plain
+// display writes each step once, without the bars.
+type pullDisplay struct {
+	w      io.Writer
+	model  string
+	plain  bool
+	status string
+	// pulled records that a pull started, open that a bar is being redrawn
+	// on the current line.
+	pulled, open bool
+}
+
+// update shows one progress report.
+func (d *pullDisplay) update(p ollama.PullProgress) {
+	if !d.pulled {
+		fmt.Fprintf(d.w, "Pulling %s\n", d.model)
+		d.pulled = true
+	}
+	if p.Status != d.status {
+		d.endLine()
+		d.status = p.Status
+		if p.Total == 0 || d.plain {
+			fmt.Fprintf(d.w, "  %s\n", p.Status)
+		}
+	}
+	if p.Total > 0 && !d.plain {
+		fmt.Fprintf(d.w, "\r  %s  %s / %s  %s\x1b[K",
+			engine.RenderProgressBar(p.Completed, p.Total, pullBarWidth),
+			formatBytes(p.Completed), formatBytes(p.Total), p.Status)
+		d.open = true
+	}
+}
+
+// finish ends the display with the outcome of the pull.
+func (d *pullDisplay) finish(err error) {
+	d.endLine()
+	if err != nil {
+		fmt.Fprintf(d.w, "overthink: %v\n", err)
+	}
+}
+
+// endLine moves past a bar being redrawn.
+func (d *pullDisplay) endLine() {
+	if d.open {
+		fmt.Fprintln(d.w)
+		d.open = false
+	}
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. "4.7 GiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
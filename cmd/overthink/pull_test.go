@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// pullServer is an Ollama server with no models installed whose pull streams
+// lines and then stops.
+func pullServer(t *testing.T, lines ...any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.WriteHeader(http.StatusOK)
+		case "/api/tags":
+			io.WriteString(w, `{"models": []}`)
+		case "/api/pull":
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			encoder := json.NewEncoder(w)
+			for _, line := range lines {
+				encoder.Encode(line)
+				w.(http.Flusher).Flush()
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// progress is one line of the pull stream.
+type progress struct {
+	Status    string `json:"status,omitempty"`
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+var failedPull = []any{
+	progress{Status: "pulling manifest"},
+	progress{Status: "pulling 6a0746a1ec1a", Digest: "sha256:6a0746a1ec1a", Total: 2048, Completed: 1024},
+	progress{Status: "pulling 6a0746a1ec1a", Digest: "sha256:6a0746a1ec1a", Total: 2048, Completed: 2048},
+	progress{Status: "verifying sha256 digest"},
+	progress{Error: "max retries exceeded: connection reset"},
+}
+
+func TestPullDisplay(t *testing.T) {
+	srv := pullServer(t, failedPull...)
+	var out bytes.Buffer
+	pullModelsFrom(&out, srv.URL, []string{"llama3"}, nil, false)
+	got := out.String()
+
+	// The download bar is redrawn in place and closed before the next step;
+	// the error ends the display on a line of its own.
+	want := []string{
+		"Pulling llama3\n",
+		"  pulling manifest\n",
+		"\r  ", "1.0 KiB / 2.0 KiB  pulling 6a0746a1ec1a\x1b[K",
+		"\r  ", "2.0 KiB / 2.0 KiB  pulling 6a0746a1ec1a\x1b[K\n",
+		"  verifying sha256 digest\n",
+		"overthink: ollama model pull failed", "max retries exceeded: connection reset\n",
+	}
+	rest := got
+	for _, w := range want {
+		i := strings.Index(rest, w)
+		if i < 0 {
+			t.Fatalf("output lacks %q after the earlier parts:\n%q", w, got)
+		}
+		rest = rest[i+len(w):]
+	}
+	if rest != "" {
+		t.Errorf("unexpected trailing output %q", rest)
+	}
+}
+
+func TestPullDisplayPlain(t *testing.T) {
+	srv := pullServer(t, failedPull...)
+	var out bytes.Buffer
+	pullModelsFrom(&out, srv.URL, []string{"llama3"}, nil, true)
+	got := out.String()
+
+	if strings.ContainsAny(got, "\r\x1b") {
+		t.Errorf("plain output redraws lines: %q", got)
+	}
+	wantLines := []string{
+		"Pulling llama3",
+		"  pulling manifest",
+		"  pulling 6a0746a1ec1a",
+		"  verifying sha256 digest",
+	}
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != len(wantLines)+1 {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(wantLines)+1, got)
+	}
+	for i, w := range wantLines {
+		if lines[i] != w {
+			t.Errorf("line %d = %q, want %q", i, lines[i], w)
+		}
+	}
+	if last := lines[len(lines)-1]; !strings.Contains(last, "max retries exceeded") {
+		t.Errorf("last line = %q, want the pull error", last)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{4_700_000_000, "4.4 GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rishichawda/overthinker/internal/backend"
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+const replPrompt = "overthink> "
+
+const replHelpText = `Type a question to overthink it, or a command:
+  /thinker <spec>      switch thinker (llama3, local, llama3,local, ensemble:...)
+  /seed <n>|off        fix the local engine's seed, or go back to the clock
+  /output full|brief   full report or a condensed one
+  /help                show this message
+  /quit                end the session (or press Ctrl-D)
+`
+
+// Output modes selectable with /output.
+const (
+	outputFull  = "full"
+	outputBrief = "brief"
+)
+
+// repl is the state of an interactive session.
+type repl struct {
+	specs  []string
+	seed   int64
+	output string
+	opts   backend.Options
+
+	thinker   engine.Thinker
+	formatter *engine.Formatter
+	tally     engine.Tally
+}
+
+// runREPL reads questions and slash-commands until EOF or /quit, then prints
+// a session summary. The thinker is built once and reused, so Ollama's
+// preflight checks run only for the first question.
+func runREPL(specs []string, opts backend.Options, formatter *engine.Formatter) {
+	r := &repl{
+		specs:     specs,
+		output:    outputFull,
+		opts:      opts,
+		formatter: formatter,
+	}
+	if err := r.rebuild(); err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		exit(1)
+	}
+
+	fmt.Fprintf(os.Stdout, "overthink interactive mode -- thinker: %s. /help for commands.\n", r.thinkerName())
+	reader := newLineReader(replPrompt)
+	for {
+		line, err := reader.ReadLine()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			break
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "/") {
+			if !r.command(line) {
+				break
+			}
+			continue
+		}
+		r.ask(line)
+	}
+
+	r.formatter.PrintSessionSummary(&r.tally)
+}
+
+// ask analyzes one question and renders it in the current output mode.
+func (r *repl) ask(question string) {
+	result, err := r.thinker.Analyze(question)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		return
+	}
+	r.tally.Add(result)
+	recordHistory(result.Attempts[len(result.Attempts)-1].Thinker, result)
+	addRiskProfile(result, question, r.opts.Context)
+
+	if r.output == outputBrief {
+		r.formatter.PrintBrief(result)
+		return
+	}
+	r.formatter.PrintAttempts(result.Attempts)
+	if len(r.specs) > 0 {
+		r.formatter.PrintModelHeader(result.Attempts[len(result.Attempts)-1].Thinker)
+	}
+	r.formatter.Print(result)
+}
+
+// command executes a slash-command. It returns false when the session
+// should end.
+func (r *repl) command(line string) bool {
+	name, arg, _ := strings.Cut(strings.TrimPrefix(line, "/"), " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case "quit", "exit", "q":
+		return false
+
+	case "help", "?":
+		fmt.Fprint(os.Stdout, replHelpText)
+
+	case "thinker":
+		if arg == "" {
+			fmt.Fprintf(os.Stdout, "thinker: %s\n", r.thinkerName())
+			return true
+		}
+		previous := r.specs
+		r.specs = backend.Split(arg)
+		if err := r.rebuild(); err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			r.specs = previous
+			r.rebuild()
+			return true
+		}
+		fmt.Fprintf(os.Stdout, "thinker: %s\n", r.thinkerName())
+
+	case "seed":
+		previous := r.seed
+		switch arg {
+		case "":
+			fmt.Fprintf(os.Stdout, "seed: %s\n", r.seedName())
+			return true
+		case "off":
+			r.seed = 0
+		default:
+			seed, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil || seed == 0 {
+				fmt.Fprintf(os.Stderr, "overthink: seed must be a non-zero integer or \"off\"\n")
+				return true
+			}
+			r.seed = seed
+		}
+		if err := r.rebuild(); err != nil {
+			fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+			r.seed = previous
+			r.rebuild()
+			return true
+		}
+		fmt.Fprintf(os.Stdout, "seed: %s\n", r.seedName())
+
+	case "output":
+		switch arg {
+		case outputFull, outputBrief:
+			r.output = arg
+			fmt.Fprintf(os.Stdout, "output: %s\n", r.output)
+		default:
+			fmt.Fprintf(os.Stderr, "overthink: unknown output mode %q (want %s or %s)\n", arg, outputFull, outputBrief)
+		}
+
+	default:
+		fmt.Fprintf(os.Stderr, "overthink: unknown command /%s (try /help)\n", name)
+	}
+	return true
+}
+
+// rebuild constructs the thinker for the current specs and seed.
+func (r *repl) rebuild() error {
+	specs := []string{backend.Local}
+	if len(r.specs) > 0 {
+		specs = append([]string(nil), r.specs...)
+	}
+	// As in the TUI, a seed reaches every thinker: local engines named in
+	// the chain, the local fallback and the sampling of Ollama models.
+	opts := r.opts
+	if r.seed != 0 {
+		for i, spec := range specs {
+			if backend.IsLocal(spec) {
+				specs[i] = fmt.Sprintf("%s:%d", backend.Local, r.seed)
+			}
+		}
+		seed := int(r.seed)
+		opts.Generation.Seed = &seed
+	}
+	c, err := backend.NewChain(specs, opts)
+	if err != nil {
+		return err
+	}
+	r.thinker = c
+	return nil
+}
+
+func (r *repl) thinkerName() string {
+	if len(r.specs) == 0 {
+		return backend.Local
+	}
+	return strings.Join(r.specs, ",")
+}
+
+func (r *repl) seedName() string {
+	if r.seed == 0 {
+		return "off (time-based)"
+	}
+	return strconv.FormatInt(r.seed, 10)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/history"
+	"github.com/rishichawda/overthinker/internal/openai"
+)
+
+// TestREPLSeedIsReproducible asks the same question twice after /seed and
+// expects the same report, both from the built-in engine and from the local
+// fallback behind an unreachable server.
+func TestREPLSeedIsReproducible(t *testing.T) {
+	t.Setenv(history.EnvPath, history.Off)
+	srv := httptest.NewServer(nil)
+	srv.Close()
+	t.Setenv(openai.EnvBaseURL, srv.URL)
+
+	for _, specs := range [][]string{nil, {"openai:gpt-test"}} {
+		var out bytes.Buffer
+		r := &repl{specs: specs, output: outputFull, formatter: engine.NewFormatter(&out)}
+		if err := r.rebuild(); err != nil {
+			t.Fatal(err)
+		}
+		r.command("/seed 7")
+		// The full report's attempt trail shows timings; the brief one does not.
+		r.command("/output brief")
+
+		var reports []string
+		for range 2 {
+			out.Reset()
+			r.ask("Should I reply all?")
+			reports = append(reports, out.String())
+		}
+		if reports[0] == "" || reports[0] != reports[1] {
+			t.Errorf("%s: reports differ after /seed:\n%s\n%s", r.thinkerName(), reports[0], reports[1])
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/history"
+)
+
+const statsUsageText = `overthink stats -- chart your overthinking history
+
+Usage:
+  overthink stats [flags]
+
+Every analysis is logged to a small local history file (the last 500 are
+kept). This command charts the most recent ones: a sparkline of the risk
+index, a histogram of risk scores and the most common outcomes.
+
+Each entry holds the time, the thinker, the risk index and the outcomes with
+their percentages. The question itself is never written to the log.
+
+The log lives in your configuration directory; set OVERTHINK_HISTORY to a
+file path to move it, or to "off" to stop logging.
+
+Flags:
+  --last <n>          Chart only the most recent n analyses (default 50)
+  --top <n>           Number of outcomes in the column chart (default 5)
+  --ascii             Draw with plain ASCII characters only
+  --theme <name>      Color theme, as for a single question
+  --a11y              Screen-reader friendly plain text output
+
+Examples:
+  overthink stats
+  overthink stats --last 200 --top 8
+`
+
+// runStats implements the "stats" subcommand.
+func runStats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	lastFlag := fs.Int("last", 50, "number of recent analyses to chart")
+	topFlag := fs.Int("top", 5, "number of outcomes in the column chart")
+	asciiFlag := fs.Bool("ascii", false, "draw with ASCII characters only")
+	themeFlag := themeFlag(fs)
+	a11yFlag := fs.Bool("a11y", false, "screen-reader friendly plain text output")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, statsUsageText) }
+	fs.Parse(args)
+	applyTheme(*themeFlag)
+
+	if fs.NArg() != 0 || *lastFlag < 1 || *topFlag < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	path := history.DefaultPath()
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "overthink: history is disabled (%s=%s)\n", history.EnvPath, os.Getenv(history.EnvPath))
+		os.Exit(1)
+	}
+	entries, err := history.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		os.Exit(1)
+	}
+	if len(entries) > *lastFlag {
+		entries = entries[len(entries)-*lastFlag:]
+	}
+
+	scores := make([]int, len(entries))
+	var tally engine.Tally
+	for i, e := range entries {
+		scores[i] = e.RiskIndex
+		tally.Add(e.Result())
+	}
+	formatter := engine.NewFormatter(os.Stdout)
+	formatter.SetAccessible(*a11yFlag)
+	formatter.PrintHistory(scores, &tally, *topFlag, *asciiFlag || !unicodeSupported())
+}
+
+// recordHistory appends result to the history log, unless logging is
+// disabled. A failure is reported but never fatal.
+func recordHistory(thinker string, result *engine.AnalysisResult) {
+	path := history.DefaultPath()
+	if path == "" {
+		return
+	}
+	if err := history.Append(path, thinker, result); err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: could not record history: %v\n", err)
+	}
+}
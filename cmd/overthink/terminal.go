@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// defaultTerminalWidth is assumed when the terminal width cannot be determined.
+const defaultTerminalWidth = 100
+
+// terminalWidth reports the width of the output terminal in columns. It asks
+// the terminal directly when stdout is one, then falls back to $COLUMNS.
+func terminalWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultTerminalWidth
+}
+
+// unicodeSupported reports whether the terminal can be expected to display
+// Unicode block characters. It trusts the locale when one is set, and
+// otherwise assumes a modern terminal, as it does on Windows.
+func unicodeSupported() bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	v := strings.ToUpper(locale())
+	return v == "" || strings.Contains(v, "UTF-8") || strings.Contains(v, "UTF8")
+}
+
+// locale returns the locale in effect for character handling, or "" when
+// none is set.
+func locale() string {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// colorDepth reports the colors the terminal can display: 24-bit when
+// $COLORTERM says so, 256 when $TERM names a 256-color terminal, and the basic
+// 16 otherwise.
+func colorDepth() engine.ColorDepth {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return engine.ColorTrue
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return engine.Color256
+	}
+	return engine.Color16
+}
+
+// dramaticPacing returns the pacing for --dramatic at the given speed, or nil
+// when the reveal is off or would do harm: accessible output is read linearly
+// by screen readers, and when out is not a terminal the redrawn frames would
+// end up in a file or pipe.
+func dramaticPacing(on, accessible bool, out *os.File, speed float64) *engine.Pacing {
+	if !on || accessible || !term.IsTerminal(int(out.Fd())) {
+		return nil
+	}
+	return engine.DefaultPacing(speed)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+func TestDramaticPacingNeedsTerminal(t *testing.T) {
+	out, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	tests := []struct {
+		name           string
+		on, accessible bool
+	}{
+		{"off", false, false},
+		{"not a terminal", true, false},
+		{"accessible", true, true},
+	}
+	for _, tt := range tests {
+		if p := dramaticPacing(tt.on, tt.accessible, out, 1); p != nil {
+			t.Errorf("%s: dramaticPacing = %+v, want nil", tt.name, p)
+		}
+	}
+}
+
+func TestUnicodeSupported(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the locale is not consulted on Windows")
+	}
+	tests := []struct {
+		lcAll, lcCType, lang string
+		want                 bool
+	}{
+		{want: true},
+		{lang: "en_US.UTF-8", want: true},
+		{lang: "de_DE.utf8", want: true},
+		{lang: "C", want: false},
+		{lang: "en_US.UTF-8", lcAll: "POSIX", want: false},
+		{lang: "C", lcCType: "C.UTF-8", want: true},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_CTYPE", tt.lcCType)
+		t.Setenv("LANG", tt.lang)
+		if got := unicodeSupported(); got != tt.want {
+			t.Errorf("LC_ALL=%q LC_CTYPE=%q LANG=%q: unicodeSupported() = %v, want %v",
+				tt.lcAll, tt.lcCType, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestColorDepth(t *testing.T) {
+	tests := []struct {
+		colorterm, term string
+		want            engine.ColorDepth
+	}{
+		{"", "xterm", engine.Color16},
+		{"", "xterm-256color", engine.Color256},
+		{"truecolor", "xterm", engine.ColorTrue},
+		{"24BIT", "xterm-256color", engine.ColorTrue},
+		{"yes", "screen-256color", engine.Color256},
+	}
+	for _, tt := range tests {
+		t.Setenv("COLORTERM", tt.colorterm)
+		t.Setenv("TERM", tt.term)
+		if got := colorDepth(); got != tt.want {
+			t.Errorf("COLORTERM=%q TERM=%q: colorDepth() = %d, want %d", tt.colorterm, tt.term, got, tt.want)
+		}
+	}
+}
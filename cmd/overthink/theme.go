@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// envTheme names the environment variable holding the theme used when no
+// --theme flag is given.
+const envTheme = "OVERTHINK_THEME"
+
+// themeFlag registers the --theme flag on fs, defaulting to $OVERTHINK_THEME.
+func themeFlag(fs *flag.FlagSet) *string {
+	return fs.String("theme", os.Getenv(envTheme), "color theme: a built-in name or a JSON theme file")
+}
+
+// applyTheme makes the named theme the style of all output, exiting with an
+// error if it cannot be loaded. An empty name keeps the default theme.
+func applyTheme(name string) {
+	if name == "" {
+		return
+	}
+	theme, err := loadTheme(name)
+	if err == nil {
+		err = engine.SetTheme(theme, colorDepth())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "overthink: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// loadTheme resolves a theme name: a path to a JSON file, a built-in theme,
+// or <name>.json in the user's themes directory.
+func loadTheme(name string) (engine.Theme, error) {
+	if strings.ContainsRune(name, filepath.Separator) || strings.HasSuffix(name, ".json") {
+		return engine.LoadTheme(name)
+	}
+	if theme, ok := engine.ThemeNamed(name); ok {
+		return theme, nil
+	}
+	if dir := configDir("themes"); dir != "" {
+		path := filepath.Join(dir, name+".json")
+		if _, err := os.Stat(path); err == nil {
+			return engine.LoadTheme(path)
+		}
+	}
+	return engine.Theme{}, fmt.Errorf("unknown theme %q (want %s, or a JSON theme file)",
+		name, strings.Join(engine.ThemeNames(), ", "))
+}
+
+// configDir returns the named subdirectory of overthink's configuration
+// directory, where user themes and personas are looked up by name, or "" if
+// the configuration directory cannot be determined. An empty name returns the
+// configuration directory itself.
+func configDir(name string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "overthink", name)
+}
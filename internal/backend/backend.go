@@ -0,0 +1,280 @@
+// Package backend turns --thinker specifications from the command line into
+// engine.Thinker implementations.
+//
+// A specification takes one of these forms:
+//
+//	local                  the built-in engine, time-seeded
+//	local:<seed>           the built-in engine with a fixed seed
+//	ensemble:<a>,<b>,...   a consensus of several specifications
+//	hybrid:<model>         local statistics with prose from an Ollama model
+//	openai:<model>         a model behind an OpenAI-compatible API, located
+//	                       via OPENAI_BASE_URL and OPENAI_API_KEY
+//	<model>                an Ollama model such as "llama3" or "mistral:7b"
+//
+// Several specifications may be given as a comma-separated list. Because an
+// ensemble's members are themselves comma-separated, an ensemble consumes the
+// remainder of the list it appears in.
+package backend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rishichawda/overthinker/internal/chain"
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/ensemble"
+	"github.com/rishichawda/overthinker/internal/local"
+	"github.com/rishichawda/overthinker/internal/ollama"
+	"github.com/rishichawda/overthinker/internal/openai"
+	"github.com/rishichawda/overthinker/internal/persona"
+)
+
+// Local is the specification that selects the built-in local engine.
+const Local = "local"
+
+// Specification prefixes for parameterised backends.
+const (
+	localPrefix    = Local + ":"
+	ensemblePrefix = "ensemble:"
+	hybridPrefix   = "hybrid:"
+	openaiPrefix   = "openai:"
+)
+
+// Split parses a comma-separated list of specifications, trimming whitespace
+// and dropping empty entries. An ensemble specification swallows every entry
+// after it.
+func Split(list string) []string {
+	var specs []string
+	parts := strings.Split(list, ",")
+	for i, s := range parts {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if strings.HasPrefix(s, ensemblePrefix) {
+			rest := append([]string{s}, parts[i+1:]...)
+			return append(specs, strings.Join(rest, ","))
+		}
+		specs = append(specs, s)
+	}
+	return specs
+}
+
+// Options configures the thinkers built from specifications.
+type Options struct {
+	// Timeout bounds each remote request; a non-positive value keeps the
+	// backend's default.
+	Timeout time.Duration
+	// Retries is the number of extra attempts a chain link gets after a
+	// transient failure.
+	Retries int
+	// Context is background material that accompanies every question, such
+	// as the contents of --context-file. LLM backends include it in the
+	// prompt; the local engine mines it for risk keywords.
+	Context string
+	// Intensity sets how dramatic every thinker is: the local engine's
+	// ranges and templates, and the LLM backends' prompt and temperature.
+	Intensity engine.Intensity
+	// Persona, when set, narrates every analysis: the local engine uses its
+	// templates and the LLM backends its system prompt.
+	Persona *persona.Persona
+	// Generation holds the options sent to Ollama models. Its Seed also
+	// seeds the local engine wherever no seed of its own is given, so that a
+	// seeded run is reproducible from end to end.
+	Generation ollama.Generation
+	// Aliases map names of the user's choosing to Ollama models, so that
+	// e.g. "fast" runs "phi3:mini".
+	Aliases map[string]string
+}
+
+// LocalEngine returns the built-in engine configured by the options, with the
+// given seed, or with the seed of the options when seed is nil. It is
+// time-seeded only when neither is set.
+func (o Options) LocalEngine(seed *int64) *local.Engine {
+	e := &local.Engine{Context: o.Context, Intensity: o.Intensity, Persona: o.Persona}
+	o.seed(e, seed)
+	return e
+}
+
+// filler returns the engine that fills in fields an LLM backend leaves out.
+func (o Options) filler() *local.Engine {
+	e := &local.Engine{Intensity: o.Intensity, Persona: o.Persona}
+	o.seed(e, nil)
+	return e
+}
+
+// seed seeds e with seed, or with the generation seed when seed is nil. A
+// seed of 0 is a seed like any other.
+func (o Options) seed(e *local.Engine, seed *int64) {
+	if seed == nil && o.Generation.Seed != nil {
+		s := int64(*o.Generation.Seed)
+		seed = &s
+	}
+	if seed != nil {
+		e.Seed, e.Seeded = *seed, true
+	}
+}
+
+// New builds the Thinker named by spec.
+func New(spec string, opts Options) (engine.Thinker, error) {
+	timeout := opts.Timeout
+	switch {
+	case spec == Local:
+		return opts.LocalEngine(nil), nil
+
+	case strings.HasPrefix(spec, localPrefix):
+		seed, err := strconv.ParseInt(strings.TrimPrefix(spec, localPrefix), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid local seed in %q: %w", spec, err)
+		}
+		return opts.LocalEngine(&seed), nil
+
+	case strings.HasPrefix(spec, ensemblePrefix):
+		specs := Split(strings.TrimPrefix(spec, ensemblePrefix))
+		if len(specs) == 0 {
+			return nil, fmt.Errorf("ensemble %q has no members", spec)
+		}
+		members, err := NewAll(specs, opts)
+		if err != nil {
+			return nil, err
+		}
+		e := ensemble.New(members)
+		if timeout > 0 {
+			e.Timeout = timeout
+		}
+		return e, nil
+
+	case strings.HasPrefix(spec, hybridPrefix):
+		model := strings.TrimPrefix(spec, hybridPrefix)
+		if model == "" {
+			return nil, fmt.Errorf("hybrid %q names no model", spec)
+		}
+		h := ollama.NewHybrid(model)
+		h.Context = opts.Context
+		h.Intensity = opts.Intensity
+		h.Persona = opts.Persona
+		h.Generation = opts.Generation
+		h.Aliases = opts.Aliases
+		h.Filler = opts.filler()
+		h.Statistician = opts.LocalEngine(nil)
+		if timeout > 0 {
+			h.Timeout = timeout
+		}
+		return h, nil
+
+	case strings.HasPrefix(spec, openaiPrefix):
+		model := strings.TrimPrefix(spec, openaiPrefix)
+		if model == "" {
+			return nil, fmt.Errorf("openai %q names no model", spec)
+		}
+		client := openai.NewClient(model)
+		client.Context = opts.Context
+		client.Intensity = opts.Intensity
+		client.Persona = opts.Persona
+		client.Generation = opts.Generation
+		client.Filler = opts.filler()
+		if timeout > 0 {
+			client.Timeout = timeout
+		}
+		return client, nil
+	}
+
+	client := ollama.NewClient(spec)
+	client.Context = opts.Context
+	client.Intensity = opts.Intensity
+	client.Persona = opts.Persona
+	client.Generation = opts.Generation
+	client.Aliases = opts.Aliases
+	client.Filler = opts.filler()
+	if timeout > 0 {
+		client.Timeout = timeout
+	}
+	return client, nil
+}
+
+// NewAll builds a NamedThinker for every specification in specs.
+func NewAll(specs []string, opts Options) ([]engine.NamedThinker, error) {
+	thinkers := make([]engine.NamedThinker, len(specs))
+	for i, spec := range specs {
+		t, err := New(spec, opts)
+		if err != nil {
+			return nil, err
+		}
+		thinkers[i] = engine.NamedThinker{Name: spec, Thinker: t}
+	}
+	return thinkers, nil
+}
+
+// NewChain builds a fallback chain over specs. The built-in engine is
+// appended when the list does not already end with it, so the chain always
+// produces a result. Missing models and unreachable servers are treated as
+// permanent failures and are not retried.
+func NewChain(specs []string, opts Options) (*chain.Chain, error) {
+	if len(specs) == 0 || !IsLocal(specs[len(specs)-1]) {
+		specs = append(specs, Local)
+	}
+	links, err := NewAll(specs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c := chain.New(links)
+	c.Retries = opts.Retries
+	c.FailFast = []error{
+		ollama.ErrModelNotFound, ollama.ErrOllamaNotFound,
+		openai.ErrModelNotFound, openai.ErrServerNotFound,
+	}
+	if opts.Timeout > 0 {
+		c.Timeout = opts.Timeout
+	}
+	return c, nil
+}
+
+// IsLocal reports whether spec selects the built-in engine, seeded or not.
+func IsLocal(spec string) bool {
+	return spec == Local || strings.HasPrefix(spec, localPrefix)
+}
+
+// OllamaModels lists the Ollama models that specs would run, including
+// ensemble members and the models behind hybrid thinkers, each once.
+func OllamaModels(specs []string) []string {
+	var models []string
+	seen := make(map[string]bool)
+	var walk func([]string)
+	walk = func(specs []string) {
+		for _, spec := range specs {
+			model := spec
+			switch {
+			case strings.HasPrefix(spec, ensemblePrefix):
+				walk(Split(strings.TrimPrefix(spec, ensemblePrefix)))
+				continue
+			case strings.HasPrefix(spec, hybridPrefix):
+				model = strings.TrimPrefix(spec, hybridPrefix)
+			case !OllamaModel(spec):
+				continue
+			}
+			if model != "" && !seen[model] {
+				seen[model] = true
+				models = append(models, model)
+			}
+		}
+	}
+	walk(specs)
+	return models
+}
+
+// OllamaModel reports whether spec names a single plain Ollama model, as
+// opposed to the local engine, a list, or a prefixed backend.
+func OllamaModel(spec string) bool {
+	if spec == "" || IsLocal(spec) || strings.Contains(spec, ",") {
+		return false
+	}
+	for _, prefix := range []string{ensemblePrefix, hybridPrefix, openaiPrefix} {
+		if strings.HasPrefix(spec, prefix) {
+			return false
+		}
+	}
+	return true
+}
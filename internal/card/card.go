@@ -0,0 +1,191 @@
+// Package card renders an analysis as a shareable image: a report card with
+// the title, a risk gauge, the probability bars and the closing line, sized
+// for social posts.
+//
+// Cards are produced as SVG or as PNG. Both are drawn in pure Go from the
+// same layout, so they look alike; the PNG uses the embedded Go Mono fonts
+// and needs no browser or external binaries.
+package card
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// Card dimensions in pixels: the 1.91:1 ratio used for link previews.
+const (
+	Width  = 1200
+	Height = 630
+)
+
+// Layout of the card, in pixels.
+const (
+	margin = 60
+
+	headerY    = 78
+	headerSize = 20
+
+	titleY      = 132
+	titleSize   = 40
+	titleLead   = 50
+	titleLines  = 2
+	dividerY    = 212
+	dividerSize = 2
+
+	gaugeCX        = 260
+	gaugeCY        = 450
+	gaugeRadius    = 160
+	gaugeThickness = 28
+	scoreSize      = 64
+	levelSize      = 22
+	captionY       = 510
+	captionSize    = 18
+
+	probX       = 520
+	probWidth   = Width - margin - probX
+	probY       = 262
+	headingSize = 22
+	labelSize   = 18
+	barHeight   = 14
+	probStep    = 54
+	maxProbs    = 5
+
+	closingY    = 588
+	closingSize = 20
+)
+
+// charWidth is the advance of a monospace glyph as a fraction of its size;
+// Go Mono, and most monospace fonts, use 0.6em.
+const charWidth = 0.6
+
+// Colors of the card, besides the risk palette shared with the terminal.
+var (
+	background = color.RGBA{0x0d, 0x11, 0x17, 0xff}
+	foreground = color.RGBA{0xe6, 0xed, 0xf3, 0xff}
+	muted      = color.RGBA{0x7d, 0x85, 0x90, 0xff}
+	track      = color.RGBA{0x21, 0x26, 0x2d, 0xff}
+)
+
+// ErrFormat is returned for an export path whose extension is neither .svg
+// nor .png.
+var ErrFormat = errors.New("unsupported image format (want .svg or .png)")
+
+// Supported reports whether path names a format Export can write.
+func Supported(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".svg" || ext == ".png"
+}
+
+// Export writes the card for result to path, choosing SVG or PNG by the
+// file's extension.
+func Export(path string, result *engine.AnalysisResult) error {
+	var render func(io.Writer, *engine.AnalysisResult) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+		render = SVG
+	case ".png":
+		render = PNG
+	default:
+		return fmt.Errorf("%w: %s", ErrFormat, path)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := render(f, result); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// titleLinesOf wraps the title to the card's width, ending the last line
+// with an ellipsis if the title does not fit.
+func titleLinesOf(title string) []string {
+	return wrap(title, columns(Width-2*margin, titleSize), titleLines)
+}
+
+// closingOf returns the closing line, shortened to fit on a single line.
+func closingOf(result *engine.AnalysisResult) string {
+	return wrap("--> "+result.ClosingLine, columns(Width-2*margin, closingSize), 1)[0]
+}
+
+// probabilitiesOf returns the probabilities shown on the card, each label
+// shortened to fit beside its percentage.
+func probabilitiesOf(result *engine.AnalysisResult) []engine.Probability {
+	probs := result.Probabilities
+	if len(probs) > maxProbs {
+		probs = probs[:maxProbs]
+	}
+	fit := columns(probWidth, labelSize) - len(" 100.0%")
+	shown := make([]engine.Probability, len(probs))
+	for i, p := range probs {
+		shown[i] = engine.Probability{Label: wrap(p.Label, fit, 1)[0], Percentage: p.Percentage}
+	}
+	return shown
+}
+
+// columns is the number of monospace characters of the given size that fit
+// in width pixels.
+func columns(width, size int) int {
+	return int(float64(width) / (charWidth * float64(size)))
+}
+
+// wrap breaks s into at most maxLines lines of at most width characters,
+// splitting on whitespace. Text that does not fit is cut with an ellipsis.
+func wrap(s string, width, maxLines int) []string {
+	var lines []string
+	line := ""
+	words := strings.Fields(s)
+	for i, word := range words {
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+		if len(lines) == maxLines {
+			last := lines[maxLines-1] + " " + strings.Join(words[i:], " ")
+			lines[maxLines-1] = ellipsize(last, width)
+			return lines
+		}
+	}
+	lines = append(lines, line)
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+	}
+	for i := range lines {
+		lines[i] = ellipsize(lines[i], width)
+	}
+	return lines
+}
+
+// ellipsize cuts s to width characters, marking the cut with an ellipsis.
+func ellipsize(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return strings.TrimRight(string(runes[:width-1]), " ") + "…"
+}
+
+// gaugeColor returns the color of the gauge arc at value (0-100) for a risk
+// index of score: lit in the risk palette up to the score, dark beyond it.
+func gaugeColor(value, score int) color.RGBA {
+	if value > score {
+		return track
+	}
+	return engine.RiskRGB(value)
+}
@@ -0,0 +1,161 @@
+package card
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/gomonoitalic"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// gaugeSamples is the number of samples per pixel side used to smooth the
+// edges of the gauge.
+const gaugeSamples = 4
+
+// PNG writes the card for result as a PNG image.
+func PNG(w io.Writer, result *engine.AnalysisResult) error {
+	fonts, err := loadFonts()
+	if err != nil {
+		return err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	r := &raster{img: img, fonts: fonts}
+
+	r.rect(0, 0, Width, Height, background)
+
+	r.text(margin, headerY, headerSize, muted, "", "start", "OVERTHINK · EMOTIONAL RISK REPORT")
+	for i, line := range titleLinesOf(result.Title) {
+		r.text(margin, titleY+i*titleLead, titleSize, engine.RGBCyan, "bold", "start", line)
+	}
+	r.rect(margin, dividerY, Width-2*margin, dividerSize, track)
+
+	score := max(0, min(result.RiskIndex, 100))
+	r.gauge(score)
+	risk := engine.RiskRGB(score)
+	r.text(gaugeCX, gaugeCY-24, scoreSize, risk, "bold", "middle", fmt.Sprint(score))
+	r.text(gaugeCX, gaugeCY+12, levelSize, risk, "", "middle", engine.RiskLevel(score))
+	r.text(gaugeCX, captionY, captionSize, muted, "", "middle", "Emotional Risk Index / 100")
+
+	r.text(probX, probY, headingSize, engine.RGBYellow, "bold", "start", "Probability Analysis")
+	for i, p := range probabilitiesOf(result) {
+		y := probY + 44 + i*probStep
+		r.text(probX, y, labelSize, foreground, "", "start", p.Label)
+		r.text(Width-margin, y, labelSize, engine.RGBCyan, "bold", "end", fmt.Sprintf("%.1f%%", p.Percentage))
+		r.rect(probX, y+10, probWidth, barHeight, track)
+		r.rect(probX, y+10, int(math.Round(float64(probWidth)*min(p.Percentage, 100)/100)), barHeight, engine.RGBCyan)
+	}
+
+	r.text(margin, closingY, closingSize, foreground, "italic", "start", closingOf(result))
+
+	return png.Encode(w, img)
+}
+
+// fontSet holds the parsed Go Mono typefaces, keyed by style.
+type fontSet map[string]*opentype.Font
+
+var (
+	fontsOnce sync.Once
+	fonts     fontSet
+	fontsErr  error
+)
+
+// loadFonts parses the embedded fonts once.
+func loadFonts() (fontSet, error) {
+	fontsOnce.Do(func() {
+		fonts = fontSet{}
+		for style, ttf := range map[string][]byte{"": gomono.TTF, "bold": gomonobold.TTF, "italic": gomonoitalic.TTF} {
+			f, err := opentype.Parse(ttf)
+			if err != nil {
+				fontsErr = fmt.Errorf("parsing embedded font: %w", err)
+				return
+			}
+			fonts[style] = f
+		}
+	})
+	return fonts, fontsErr
+}
+
+// raster draws the card's elements onto an image.
+type raster struct {
+	img   *image.RGBA
+	fonts fontSet
+}
+
+func (r *raster) rect(x, y, w, h int, c color.RGBA) {
+	draw.Draw(r.img, image.Rect(x, y, x+w, y+h), image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// text draws a line of text with its baseline at y. style and anchor are as
+// for the SVG card.
+func (r *raster) text(x, y, size int, c color.RGBA, style, anchor, content string) {
+	face, err := opentype.NewFace(r.fonts[style], &opentype.FaceOptions{
+		Size:    float64(size),
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return
+	}
+	defer face.Close()
+
+	d := &font.Drawer{Dst: r.img, Src: image.NewUniform(c), Face: face}
+	width := d.MeasureString(content)
+	start := fixed.I(x)
+	switch anchor {
+	case "middle":
+		start -= width / 2
+	case "end":
+		start -= width
+	}
+	d.Dot = fixed.Point26_6{X: start, Y: fixed.I(y)}
+	d.DrawString(content)
+}
+
+// gauge draws the semicircular risk gauge, lit up to score. Each pixel's
+// color is that of the value at its centre; its coverage is sampled on a
+// fine grid so the edges come out smooth.
+func (r *raster) gauge(score int) {
+	outer := float64(gaugeRadius)
+	inner := outer - gaugeThickness
+	for py := gaugeCY - gaugeRadius; py <= gaugeCY; py++ {
+		for px := gaugeCX - gaugeRadius; px <= gaugeCX+gaugeRadius; px++ {
+			covered := 0
+			for sy := 0; sy < gaugeSamples; sy++ {
+				for sx := 0; sx < gaugeSamples; sx++ {
+					dx := float64(px) + (float64(sx)+0.5)/gaugeSamples - gaugeCX
+					dy := gaugeCY - (float64(py) + (float64(sy)+0.5)/gaugeSamples)
+					if dist := math.Hypot(dx, dy); dy >= 0 && dist <= outer && dist >= inner {
+						covered++
+					}
+				}
+			}
+			if covered == 0 {
+				continue
+			}
+			dx, dy := float64(px)+0.5-gaugeCX, math.Max(0, gaugeCY-float64(py)-0.5)
+			value := int(100 * (1 - math.Atan2(dy, dx)/math.Pi))
+			alpha := float64(covered) / (gaugeSamples * gaugeSamples)
+			r.img.SetRGBA(px, py, blend(r.img.RGBAAt(px, py), gaugeColor(value, score), alpha))
+		}
+	}
+}
+
+// blend mixes c over base with the given opacity.
+func blend(base, c color.RGBA, alpha float64) color.RGBA {
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a)*(1-alpha) + float64(b)*alpha))
+	}
+	return color.RGBA{mix(base.R, c.R), mix(base.G, c.G), mix(base.B, c.B), 0xff}
+}
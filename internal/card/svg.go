@@ -0,0 +1,119 @@
+package card
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// svgFonts is the font stack of every text element: Go Mono where it is
+// installed, as in the PNG, and the platform's monospace font otherwise.
+const svgFonts = `'Go Mono', Menlo, Consolas, 'DejaVu Sans Mono', monospace`
+
+// SVG writes the card for result as an SVG document.
+func SVG(w io.Writer, result *engine.AnalysisResult) error {
+	bw := bufio.NewWriter(w)
+	s := &svgWriter{w: bw}
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="%s">`+"\n",
+		Width, Height, Width, Height, svgFonts)
+	s.rect(0, 0, Width, Height, background)
+
+	s.text(margin, headerY, headerSize, muted, "", "start", "OVERTHINK · EMOTIONAL RISK REPORT")
+	for i, line := range titleLinesOf(result.Title) {
+		s.text(margin, titleY+i*titleLead, titleSize, engine.RGBCyan, "bold", "start", line)
+	}
+	s.rect(margin, dividerY, Width-2*margin, dividerSize, track)
+
+	// The gauge: a dark track, then the lit part in one arc per risk band.
+	score := max(0, min(result.RiskIndex, 100))
+	s.arc(0, 100, track)
+	for _, band := range [][2]int{{0, 40}, {40, 70}, {70, 100}} {
+		if score > band[0] {
+			s.arc(band[0], min(score, band[1]), gaugeColor(band[0], score))
+		}
+	}
+	risk := engine.RiskRGB(score)
+	s.text(gaugeCX, gaugeCY-24, scoreSize, risk, "bold", "middle", fmt.Sprint(score))
+	s.text(gaugeCX, gaugeCY+12, levelSize, risk, "", "middle", engine.RiskLevel(score))
+	s.text(gaugeCX, captionY, captionSize, muted, "", "middle", "Emotional Risk Index / 100")
+
+	s.text(probX, probY, headingSize, engine.RGBYellow, "bold", "start", "Probability Analysis")
+	for i, p := range probabilitiesOf(result) {
+		y := probY + 44 + i*probStep
+		s.text(probX, y, labelSize, foreground, "", "start", p.Label)
+		s.text(Width-margin, y, labelSize, engine.RGBCyan, "bold", "end", fmt.Sprintf("%.1f%%", p.Percentage))
+		s.rect(probX, y+10, probWidth, barHeight, track)
+		s.rect(probX, y+10, int(math.Round(float64(probWidth)*min(p.Percentage, 100)/100)), barHeight, engine.RGBCyan)
+	}
+
+	s.text(margin, closingY, closingSize, foreground, "italic", "start", closingOf(result))
+
+	fmt.Fprintln(bw, "</svg>")
+	if s.err != nil {
+		return s.err
+	}
+	return bw.Flush()
+}
+
+// svgWriter writes SVG elements, remembering the first write error.
+type svgWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (s *svgWriter) printf(format string, args ...any) {
+	if s.err == nil {
+		_, s.err = fmt.Fprintf(s.w, format, args...)
+	}
+}
+
+func (s *svgWriter) rect(x, y, w, h int, c color.RGBA) {
+	s.printf(`<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", x, y, w, h, hex(c))
+}
+
+// text writes a line of text with its baseline at y. style is "bold",
+// "italic" or empty; anchor is "start", "middle" or "end".
+func (s *svgWriter) text(x, y, size int, c color.RGBA, style, anchor, content string) {
+	var attrs strings.Builder
+	switch style {
+	case "bold":
+		attrs.WriteString(` font-weight="bold"`)
+	case "italic":
+		attrs.WriteString(` font-style="italic"`)
+	}
+	if anchor != "start" {
+		fmt.Fprintf(&attrs, ` text-anchor="%s"`, anchor)
+	}
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(content))
+	s.printf(`<text x="%d" y="%d" font-size="%d" fill="%s"%s xml:space="preserve">%s</text>`+"\n",
+		x, y, size, hex(c), attrs.String(), escaped.String())
+}
+
+// arc strokes the gauge between two values on its 0-100 scale.
+func (s *svgWriter) arc(from, to int, c color.RGBA) {
+	r := float64(gaugeRadius - gaugeThickness/2)
+	x0, y0 := gaugePoint(from, r)
+	x1, y1 := gaugePoint(to, r)
+	s.printf(`<path d="M %.2f %.2f A %.2f %.2f 0 0 1 %.2f %.2f" fill="none" stroke="%s" stroke-width="%d"/>`+"\n",
+		x0, y0, r, r, x1, y1, hex(c), gaugeThickness)
+}
+
+// gaugePoint returns the point at value (0-100) on a gauge arc of radius r,
+// running from the left over the top to the right.
+func gaugePoint(value int, r float64) (float64, float64) {
+	theta := math.Pi * (1 - float64(value)/100)
+	return gaugeCX + r*math.Cos(theta), gaugeCY - r*math.Sin(theta)
+}
+
+// hex formats c as an SVG color.
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,135 @@
+// Package chain provides a fallback Thinker that tries a sequence of backends
+// in order until one of them produces a result.
+//
+// Each link gets its own timeout, and a link that runs out of time is
+// cancelled if it supports it. Transient failures are retried with
+// exponential backoff before moving on; failures recognised as permanent (a
+// missing model, for instance) skip straight to the next link. Every attempt
+// is recorded on the returned result so the user can see the full trail.
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// Default retry policy.
+const (
+	DefaultRetries    = 2
+	DefaultBackoff    = 500 * time.Millisecond
+	DefaultMaxBackoff = 8 * time.Second
+	DefaultTimeout    = 2 * time.Minute
+)
+
+// ErrExhausted is returned when every link in the chain has failed.
+var ErrExhausted = errors.New("every thinker in the chain failed")
+
+// Chain is an engine.Thinker that falls back through Links in order.
+type Chain struct {
+	// Links are tried first to last.
+	Links []engine.NamedThinker
+	// Timeout bounds each individual attempt.
+	Timeout time.Duration
+	// Retries is the number of extra attempts a link gets after a transient
+	// failure. A negative value counts as none.
+	Retries int
+	// Backoff is the delay before the first retry; it doubles on each
+	// subsequent retry up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// FailFast lists errors that are never worth retrying. A link failing
+	// with an error matching any of them (via errors.Is) is abandoned at once.
+	FailFast []error
+	// Sleep waits between retries. It defaults to time.Sleep and exists so
+	// callers can substitute a fake clock.
+	Sleep func(time.Duration)
+}
+
+// New constructs a Chain over links with the default retry policy.
+func New(links []engine.NamedThinker) *Chain {
+	return &Chain{
+		Links:      links,
+		Timeout:    DefaultTimeout,
+		Retries:    DefaultRetries,
+		Backoff:    DefaultBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+		Sleep:      time.Sleep,
+	}
+}
+
+// Analyze implements engine.Thinker. The successful result carries the full
+// attempt trail in its Attempts field. If every link fails, the returned error
+// wraps ErrExhausted and the last link's error.
+func (c *Chain) Analyze(question string) (*engine.AnalysisResult, error) {
+	return c.AnalyzeContext(context.Background(), question)
+}
+
+// AnalyzeContext implements engine.ContextThinker. Cancelling ctx cancels the
+// current attempt.
+func (c *Chain) AnalyzeContext(ctx context.Context, question string) (*engine.AnalysisResult, error) {
+	var trail []engine.Attempt
+	var lastErr error
+
+	for i, link := range c.Links {
+		if i > 0 {
+			slog.Info("falling back", "from", c.Links[i-1].Name, "to", link.Name)
+		}
+		delay := c.Backoff
+		for try := 1; try <= max(c.Retries, 0)+1; try++ {
+			start := time.Now()
+			result, err := engine.AnalyzeWithTimeout(ctx, link.Thinker, question, c.Timeout)
+			trail = append(trail, engine.Attempt{
+				Thinker: link.Name,
+				Try:     try,
+				Err:     err,
+				Latency: time.Since(start),
+			})
+
+			latency := trail[len(trail)-1].Latency
+			if err == nil {
+				slog.Info("thinker answered", "thinker", link.Name, "try", try, "elapsed", latency)
+				result.Attempts = trail
+				return result, nil
+			}
+			slog.Info("thinker failed", "thinker", link.Name, "try", try, "elapsed", latency,
+				"permanent", c.permanent(err) || !c.retryable(link, err), "err", err)
+			lastErr = err
+			if c.permanent(err) || !c.retryable(link, err) || try > c.Retries {
+				break
+			}
+
+			c.Sleep(delay)
+			delay *= 2
+			if c.MaxBackoff > 0 && delay > c.MaxBackoff {
+				delay = c.MaxBackoff
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w (%d attempts): %w", ErrExhausted, len(trail), lastErr)
+}
+
+// retryable reports whether retrying link after err is safe. A timed-out
+// thinker that cannot be cancelled is still busy with the abandoned attempt,
+// so retrying it would only pile more work onto a slow backend.
+func (c *Chain) retryable(link engine.NamedThinker, err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	return !errors.Is(err, engine.ErrTimeout) || engine.Cancellable(link.Thinker)
+}
+
+// permanent reports whether err matches one of the FailFast errors.
+func (c *Chain) permanent(err error) bool {
+	for _, target := range c.FailFast {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
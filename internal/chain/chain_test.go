@@ -0,0 +1,107 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// stuckThinker never answers on its own.
+type stuckThinker struct {
+	calls atomic.Int32
+	block chan struct{}
+}
+
+func (s *stuckThinker) Analyze(string) (*engine.AnalysisResult, error) {
+	s.calls.Add(1)
+	<-s.block
+	return nil, errors.New("released")
+}
+
+// cancellableThinker answers only by giving up when its context ends.
+type cancellableThinker struct {
+	calls, cancelled atomic.Int32
+}
+
+func (c *cancellableThinker) Analyze(q string) (*engine.AnalysisResult, error) {
+	return c.AnalyzeContext(context.Background(), q)
+}
+
+func (c *cancellableThinker) AnalyzeContext(ctx context.Context, _ string) (*engine.AnalysisResult, error) {
+	c.calls.Add(1)
+	<-ctx.Done()
+	c.cancelled.Add(1)
+	return nil, ctx.Err()
+}
+
+type answerThinker struct{}
+
+func (answerThinker) Analyze(string) (*engine.AnalysisResult, error) {
+	return &engine.AnalysisResult{Title: "ANSWER"}, nil
+}
+
+func newTestChain(links ...engine.NamedThinker) *Chain {
+	c := New(links)
+	c.Timeout = 20 * time.Millisecond
+	c.Retries = 2
+	c.Sleep = func(time.Duration) {}
+	return c
+}
+
+func TestTimeoutOfUncancellableThinkerIsNotRetried(t *testing.T) {
+	stuck := &stuckThinker{block: make(chan struct{})}
+	defer close(stuck.block)
+
+	c := newTestChain(
+		engine.NamedThinker{Name: "stuck", Thinker: stuck},
+		engine.NamedThinker{Name: "local", Thinker: answerThinker{}},
+	)
+	result, err := c.Analyze("q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stuck.calls.Load(); got != 1 {
+		t.Errorf("stuck thinker called %d times, want 1", got)
+	}
+	if len(result.Attempts) != 2 || !errors.Is(result.Attempts[0].Err, engine.ErrTimeout) {
+		t.Errorf("attempts = %+v, want a timeout then an answer", result.Attempts)
+	}
+}
+
+func TestTimeoutCancelsAndRetriesCancellableThinker(t *testing.T) {
+	slow := &cancellableThinker{}
+	c := newTestChain(
+		engine.NamedThinker{Name: "slow", Thinker: slow},
+		engine.NamedThinker{Name: "local", Thinker: answerThinker{}},
+	)
+	if _, err := c.Analyze("q"); err != nil {
+		t.Fatal(err)
+	}
+	if calls, cancelled := slow.calls.Load(), slow.cancelled.Load(); calls != 3 || cancelled != 3 {
+		t.Errorf("slow thinker called %d times and cancelled %d, want 3 and 3", calls, cancelled)
+	}
+}
+
+func TestNegativeRetriesStillTriesEveryLink(t *testing.T) {
+	failing := &cancellableThinker{}
+	c := newTestChain(
+		engine.NamedThinker{Name: "slow", Thinker: failing},
+		engine.NamedThinker{Name: "local", Thinker: answerThinker{}},
+	)
+	c.Retries = -1
+
+	result, err := c.Analyze("q")
+	if err != nil {
+		t.Fatalf("err = %v, want an answer from the last link", err)
+	}
+	if got := failing.calls.Load(); got != 1 {
+		t.Errorf("first link tried %d times, want 1", got)
+	}
+	if len(result.Attempts) != 2 {
+		t.Errorf("attempt trail = %+v, want one try per link", result.Attempts)
+	}
+}
@@ -0,0 +1,221 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetAccessible switches the formatter to screen-reader friendly output, or
+// back. Accessible output is plain, linear text: every section is labelled,
+// charts become sentences ("Risk: 72 out of 100, alarming"), meaning is never
+// carried by color alone, and no escape sequences or block characters are
+// written. Charts selected with SetChart and the dramatic reveal are ignored.
+func (f *Formatter) SetAccessible(on bool) {
+	f.accessible = on
+}
+
+// printAccessible is Print for accessible output.
+func (f *Formatter) printAccessible(result *AnalysisResult) {
+	f.line("")
+	f.linef("Title: %s", result.Title)
+	if len(result.Repairs) > 0 {
+		f.linef("Recovered: %s.", strings.Join(result.Repairs, "; "))
+	}
+
+	f.plainSection(f.headings.Summary)
+	f.line(result.Summary)
+
+	f.plainSection(f.headings.Probabilities)
+	f.linef("%s:", Plural(len(result.Probabilities), "outcome"))
+	for i, p := range result.Probabilities {
+		f.linef("%d. %s: %s.", i+1, percent(p.Percentage), p.Label)
+	}
+
+	f.plainSection(f.headings.Risk)
+	f.line(riskSentence(result.RiskIndex))
+	if result.PreviousRiskIndex != nil {
+		f.line(riskDeltaSentence(result.RiskIndex, *result.PreviousRiskIndex))
+	}
+	if len(result.RiskProfile) > 0 {
+		parts := make([]string, len(result.RiskProfile))
+		for i, c := range result.RiskProfile {
+			parts[i] = fmt.Sprintf("%s %d", c.Name, c.Score)
+		}
+		f.linef("Risk profile, out of 100: %s.", strings.Join(parts, ", "))
+	}
+	if c := result.Consensus; c != nil {
+		f.linef("Inter-rater disagreement: plus or minus %.1f.", c.Disagreement)
+		scores := make([]string, len(c.Members))
+		for i, m := range c.Members {
+			scores[i] = fmt.Sprintf("%s %d", m, c.RiskScores[i])
+		}
+		f.linef("Panel: %s.", strings.Join(scores, ", "))
+		for _, failure := range c.Failures {
+			f.linef("Absent: %s.", failure)
+		}
+	}
+
+	f.plainSection(f.headings.Citations)
+	for _, c := range result.Citations {
+		f.linef("Citation %d: %s.", c.Index, c.Source)
+	}
+
+	f.plainSection(f.headings.Conclusion)
+	f.line(result.Conclusion)
+
+	f.plainSection("Closing Line")
+	f.line(result.ClosingLine)
+	f.line("")
+}
+
+// printBriefAccessible is PrintBrief for accessible output.
+func (f *Formatter) printBriefAccessible(result *AnalysisResult) {
+	f.line("")
+	f.linef("Title: %s", result.Title)
+	f.line(riskSentence(result.RiskIndex))
+	if result.PreviousRiskIndex != nil {
+		f.line(riskDeltaSentence(result.RiskIndex, *result.PreviousRiskIndex))
+	}
+	if p, ok := (Run{Result: result}).TopProbability(); ok {
+		f.linef("Most likely: %s: %s.", percent(p.Percentage), p.Label)
+	}
+	f.linef("Closing line: %s", result.ClosingLine)
+	f.line("")
+}
+
+// printSessionSummaryAccessible is PrintSessionSummary for accessible output.
+func (f *Formatter) printSessionSummaryAccessible(t *Tally) {
+	f.plainSection("Session Summary")
+	if t.Count == 0 {
+		f.line("No questions were overthought. Suspiciously well-adjusted.")
+		f.line("")
+		return
+	}
+	avg := t.AverageRisk()
+	f.linef("Questions overthought: %d.", t.Count)
+	f.linef("Average risk index: %.1f out of 100, %s.", avg, RiskLevel(int(avg)))
+	if top := t.TopLabels(1); len(top) > 0 {
+		f.linef("Most frequent outcome: %s, %s.", top[0].Label, Plural(top[0].Count, "time"))
+	}
+	f.linef("Citations fabricated: %d.", t.Citations)
+	f.line("")
+}
+
+// printHistoryAccessible is PrintHistory for accessible output: the
+// sparkline becomes a trend and the charts become lists.
+func (f *Formatter) printHistoryAccessible(scores []int, t *Tally, top int) {
+	f.plainSection("Risk History")
+	if len(scores) == 0 {
+		f.line("No analyses logged yet. Overthink something first.")
+		f.line("")
+		return
+	}
+	latest := scores[len(scores)-1]
+	f.linef("%s logged.", Plural(len(scores), "analysis"))
+	f.linef("Average risk index: %.1f out of 100, %s.", t.AverageRisk(), RiskLevel(int(t.AverageRisk())))
+	f.linef("Latest risk index: %d out of 100, %s.", latest, RiskLevel(latest))
+	if len(scores) > 1 {
+		f.line(riskDeltaSentence(latest, scores[len(scores)-2]))
+		lowest, highest := scores[0], scores[0]
+		for _, s := range scores {
+			lowest, highest = min(lowest, s), max(highest, s)
+		}
+		f.linef("Lowest %d, highest %d.", lowest, highest)
+	}
+
+	f.plainSection("Risk Distribution")
+	var counts [10]int
+	for _, score := range scores {
+		counts[min(clampScore(score)/10, 9)]++
+	}
+	for band, c := range counts {
+		if c == 0 {
+			continue
+		}
+		low, high := band*10, band*10+9
+		if band == 9 {
+			high = 100
+		}
+		f.linef("Scores %d to %d, %s: %s.", low, high, RiskLevel(low), Plural(c, "analysis"))
+	}
+
+	if labels := t.TopLabels(top); len(labels) > 0 {
+		f.plainSection("Most Common Outcomes")
+		for i, l := range labels {
+			f.linef("%d. %s: %s.", i+1, l.Label, Plural(l.Count, "time"))
+		}
+	}
+	f.line("")
+}
+
+// printSummaryAccessible is the comparison summary table for accessible
+// output, one sentence per thinker.
+func (f *Formatter) printSummaryAccessible(runs []Run) {
+	f.plainSection("Comparison Summary")
+	for _, r := range runs {
+		if r.Err != nil {
+			f.linef("%s failed after %s: %s.", r.Name, formatLatency(r.Latency), r.Err)
+			continue
+		}
+		sentence := fmt.Sprintf("%s: risk %d out of 100, %s, in %s.",
+			r.Name, r.Result.RiskIndex, RiskLevel(r.Result.RiskIndex), formatLatency(r.Latency))
+		if p, ok := r.TopProbability(); ok {
+			sentence += fmt.Sprintf(" Most likely: %s: %s.", percent(p.Percentage), p.Label)
+		}
+		f.line(sentence)
+	}
+	f.line("")
+}
+
+// printAttemptsAccessible is PrintAttempts for accessible output.
+func (f *Formatter) printAttemptsAccessible(attempts []Attempt) {
+	f.plainSection("Attempt Trail")
+	for _, a := range attempts {
+		outcome := "succeeded"
+		if a.Err != nil {
+			outcome = "failed: " + a.Err.Error()
+		}
+		f.linef("%s, try %d, %s, %s.", a.Thinker, a.Try, formatLatency(a.Latency), outcome)
+	}
+}
+
+// plainSection starts a labelled section of accessible output.
+func (f *Formatter) plainSection(name string) {
+	f.line("")
+	f.linef("Section: %s.", name)
+}
+
+// riskSentence describes a risk index in words, e.g. "Risk: 72 out of 100,
+// alarming."
+func riskSentence(score int) string {
+	return fmt.Sprintf("Risk: %d out of 100, %s.", score, RiskLevel(score))
+}
+
+// riskDeltaSentence is RenderRiskDelta in words.
+func riskDeltaSentence(score, previous int) string {
+	switch delta := score - previous; {
+	case delta > 0:
+		return fmt.Sprintf("Up %d since last analysis.", delta)
+	case delta < 0:
+		return fmt.Sprintf("Down %d since last analysis.", -delta)
+	default:
+		return "Unchanged since last analysis."
+	}
+}
+
+// percent spells out a percentage, since screen readers read "%" unevenly.
+func percent(p float64) string {
+	return fmt.Sprintf("%.1f percent", p)
+}
+
+// Plural formats n with noun, adding an "s" (or "es" after "is") unless n is
+// one.
+func Plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if stem, ok := strings.CutSuffix(noun, "is"); ok {
+		return fmt.Sprintf("%d %ses", n, stem)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -8,51 +8,218 @@ import (
 const (
 	// chartWidth is the total character width of all rendered bar charts.
 	chartWidth = 40
-
-	// filledBlock is the Unicode block character used for the filled bar portion.
-	filledBlock = "\u2588"
-	// emptyBlock is used for the unfilled portion of a bar.
-	emptyBlock = "\u2591"
-	// dividerChar is the box-drawing horizontal line used for section dividers.
-	dividerChar = "\u2500"
 )
 
 // RenderRiskBar renders a colored horizontal bar for the Emotional Risk Index.
 // fillColor is an ANSI color code applied to the filled portion of the bar.
 // The label line shows the numeric score; the bar line shows the visual.
 func RenderRiskBar(score int, fillColor string) string {
+	return renderRiskBar(DefaultHeadings.Risk, score, fillColor)
+}
+
+// renderRiskBar is RenderRiskBar with the label's name given.
+func renderRiskBar(name string, score int, fillColor string) string {
 	if score < 0 {
 		score = 0
 	}
 	if score > 100 {
 		score = 100
 	}
-	filled := (score * chartWidth) / 100
-	empty := chartWidth - filled
-	bar := fillColor + strings.Repeat(filledBlock, filled) + colorReset + dim(strings.Repeat(emptyBlock, empty))
-	label := fmt.Sprintf("%sEmotional Risk Index: %s%d%s/100%s",
-		colorBold,
+	bar := renderBar((score*chartWidth)/100, chartWidth, fillColor)
+	label := fmt.Sprintf("%s%s: %s%d%s/100%s",
+		colorBold, name,
 		fillColor, score, colorReset+colorBold,
 		colorReset)
 	return label + "\n" + bar
 }
 
+// RenderRiskDelta describes how far the risk index moved since a previous
+// analysis, e.g. "+12 since last analysis". Rises are red, falls green.
+func RenderRiskDelta(score, previous int) string {
+	delta := score - previous
+	switch {
+	case delta > 0:
+		return active.alarming + fmt.Sprintf("+%d", delta) + colorReset + dim(" since last analysis")
+	case delta < 0:
+		return active.calm + fmt.Sprintf("%d", delta) + colorReset + dim(" since last analysis")
+	default:
+		return dim("unchanged since last analysis")
+	}
+}
+
 // RenderProbabilityBars renders a compact bar chart for each probability entry.
 // barColor is an ANSI color code applied to the filled portion of each bar.
 func RenderProbabilityBars(probs []Probability, barColor string) string {
 	var sb strings.Builder
 	for _, p := range probs {
-		filled := int((p.Percentage / 100.0) * float64(chartWidth))
-		empty := chartWidth - filled
-		bar := barColor + strings.Repeat(filledBlock, filled) + colorReset + dim(strings.Repeat(emptyBlock, empty))
-		sb.WriteString(fmt.Sprintf("  %s%5.1f%%%s  %s  %s\n",
-			barColor, p.Percentage, colorReset,
-			bar, dim(p.Label)))
+		sb.WriteString(renderProbabilityBar(p, barColor) + "\n")
 	}
 	return sb.String()
 }
 
+// renderProbabilityBar renders the bar line for a single probability entry.
+func renderProbabilityBar(p Probability, barColor string) string {
+	bar := renderBar(int((p.Percentage/100.0)*float64(chartWidth)), chartWidth, barColor)
+	return fmt.Sprintf("  %s%5.1f%%%s  %s  %s",
+		barColor, p.Percentage, colorReset,
+		bar, dim(p.Label))
+}
+
+// RenderProgressBar renders a bar width characters wide showing completed out
+// of total, drawn with the theme's bar glyphs and followed by the percentage.
+func RenderProgressBar(completed, total int64, width int) string {
+	var fraction float64
+	if total > 0 {
+		fraction = min(float64(completed)/float64(total), 1)
+	}
+	bar := renderBar(int(fraction*float64(width)), width, active.accent)
+	return fmt.Sprintf("%s %s%5.1f%%%s", bar, active.accent, fraction*100, colorReset)
+}
+
 // RenderDivider returns a horizontal divider line of the given character width.
 func RenderDivider(width int) string {
-	return strings.Repeat(dividerChar, width)
+	return strings.Repeat(active.divider, width)
+}
+
+// renderBar renders a bar of the given total width with the first filled
+// characters drawn in fillColor and the remainder dimmed.
+func renderBar(filled, width int, fillColor string) string {
+	if filled < 0 {
+		filled = 0
+	}
+	if filled > width {
+		filled = width
+	}
+	return fillColor + strings.Repeat(active.filled, filled) + colorReset + dim(strings.Repeat(active.empty, width-filled))
+}
+
+// sparkLevels are the glyphs of a sparkline, lowest first.
+var (
+	sparkLevels      = []rune("▁▂▃▄▅▆▇█")
+	asciiSparkLevels = []rune("_.-~=+*#")
+)
+
+// ASCII stand-ins for the block and box-drawing characters, used where the
+// terminal cannot display Unicode.
+const (
+	asciiFilled  = "#"
+	asciiEmpty   = "."
+	asciiDivider = "-"
+)
+
+// RenderSparkline renders one glyph per risk score (0-100), taller for higher
+// scores and colored with the same thresholds as the risk bar. With ascii
+// set, only 7-bit characters are used.
+func RenderSparkline(scores []int, ascii bool) string {
+	levels := sparkLevels
+	if ascii {
+		levels = asciiSparkLevels
+	}
+	var sb strings.Builder
+	for _, score := range scores {
+		score = clampScore(score)
+		level := score * (len(levels) - 1) / 100
+		sb.WriteString(riskFillColor(score) + string(levels[level]) + colorReset)
+	}
+	return sb.String()
+}
+
+// RenderRiskHistogram renders how many scores fall into each band of ten
+// (0-9, 10-19, ... 90-100) as one horizontal bar per band, colored by the
+// band's risk level.
+func RenderRiskHistogram(scores []int, ascii bool) string {
+	var counts [10]int
+	for _, score := range scores {
+		counts[min(clampScore(score)/10, 9)]++
+	}
+	most := 0
+	for _, c := range counts {
+		most = max(most, c)
+	}
+
+	var sb strings.Builder
+	for band, c := range counts {
+		low, high := band*10, band*10+9
+		if band == 9 {
+			high = 100
+		}
+		filled := 0
+		if most > 0 {
+			filled = (c*chartWidth + most - 1) / most
+		}
+		fmt.Fprintf(&sb, "  %s  %s  %s\n",
+			dim(fmt.Sprintf("%3d-%-3d", low, high)),
+			renderGlyphBar(filled, chartWidth, riskFillColor(low), ascii),
+			fmt.Sprint(c))
+	}
+	return sb.String()
+}
+
+// RenderColumnChart renders counts as vertical bars height rows tall, most
+// frequent first. Columns are numbered and the labels listed in a legend
+// underneath, since they are far too long to fit beneath the bars.
+func RenderColumnChart(counts []LabelCount, height int, ascii bool) string {
+	if len(counts) == 0 || height < 1 {
+		return ""
+	}
+	filled, divider := active.filled, active.divider
+	if ascii {
+		filled, divider = asciiFilled, asciiDivider
+	}
+	most := 0
+	for _, c := range counts {
+		most = max(most, c.Count)
+	}
+
+	const columnWidth = 5
+	var sb strings.Builder
+	for row := height; row >= 0; row-- {
+		sb.WriteString("  ")
+		for _, c := range counts {
+			// Round up, so every label that appeared at all gets a bar.
+			rows := (c.Count*height + most - 1) / most
+			switch {
+			case row == rows:
+				sb.WriteString(padRight(centre(fmt.Sprint(c.Count), columnWidth-1), columnWidth))
+			case row < rows:
+				sb.WriteString(" " + active.accent + strings.Repeat(filled, columnWidth-2) + colorReset + "  ")
+			default:
+				sb.WriteString(strings.Repeat(" ", columnWidth))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("  " + dim(strings.Repeat(divider, columnWidth*len(counts))) + "\n")
+	sb.WriteString("  ")
+	for i := range counts {
+		sb.WriteString(padRight(centre(fmt.Sprint(i+1), columnWidth-1), columnWidth))
+	}
+	sb.WriteString("\n\n")
+	for i, c := range counts {
+		fmt.Fprintf(&sb, "  %s  %s\n", dimAccent(fmt.Sprintf("%2d", i+1)), c.Label)
+	}
+	return sb.String()
+}
+
+// renderGlyphBar is renderBar with a choice of Unicode or ASCII glyphs.
+func renderGlyphBar(filled, width int, fillColor string, ascii bool) string {
+	if !ascii {
+		return renderBar(filled, width, fillColor)
+	}
+	filled = max(0, min(filled, width))
+	return fillColor + strings.Repeat(asciiFilled, filled) + colorReset + dim(strings.Repeat(asciiEmpty, width-filled))
+}
+
+// centre pads plain text with spaces on both sides to width characters.
+func centre(s string, width int) string {
+	gap := width - len(s)
+	if gap <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", gap/2) + s + strings.Repeat(" ", gap-gap/2)
+}
+
+// clampScore limits a risk score to 0-100.
+func clampScore(score int) int {
+	return max(0, min(score, 100))
 }
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckStatus is the outcome of a diagnostic check.
+type CheckStatus int
+
+// Check outcomes, from best to worst.
+const (
+	CheckPass CheckStatus = iota
+	CheckWarn
+	CheckFail
+)
+
+// String returns "pass", "warn" or "fail".
+func (s CheckStatus) String() string {
+	switch s {
+	case CheckPass:
+		return "pass"
+	case CheckWarn:
+		return "warn"
+	default:
+		return "fail"
+	}
+}
+
+// color returns the risk color matching the outcome.
+func (s CheckStatus) color() string {
+	switch s {
+	case CheckPass:
+		return active.calm
+	case CheckWarn:
+		return active.concerning
+	default:
+		return active.alarming
+	}
+}
+
+// Check is one row of a diagnostics report: what was checked, under which
+// group, how it went, and the details.
+type Check struct {
+	Group  string
+	Name   string
+	Status CheckStatus
+	Detail string
+}
+
+// PrintChecks renders checks as a pass/warn/fail table, one section per
+// group in the order the groups first appear, followed by a tally.
+func (f *Formatter) PrintChecks(checks []Check) {
+	if f.accessible {
+		f.printChecksAccessible(checks)
+		return
+	}
+	nameWidth := 0
+	for _, c := range checks {
+		nameWidth = max(nameWidth, len(c.Name))
+	}
+
+	group := ""
+	for i, c := range checks {
+		if i == 0 || c.Group != group {
+			group = c.Group
+			f.line("")
+			f.linef("%s:", section(group))
+		}
+		f.linef("  %s%s%s  %s  %s",
+			c.Status.color(), strings.ToUpper(c.Status.String()), colorReset,
+			padRight(c.Name, nameWidth),
+			c.Detail)
+	}
+	f.line("")
+	f.line(dim(checkTally(checks)))
+	f.line("")
+}
+
+// printChecksAccessible is PrintChecks for accessible output.
+func (f *Formatter) printChecksAccessible(checks []Check) {
+	group := ""
+	for i, c := range checks {
+		if i == 0 || c.Group != group {
+			group = c.Group
+			f.plainSection(group)
+		}
+		f.linef("%s: %s. %s.", c.Name, c.Status, strings.TrimSuffix(c.Detail, "."))
+	}
+	f.line("")
+	f.line(checkTally(checks))
+	f.line("")
+}
+
+// checkTally counts the checks by outcome, e.g. "7 passed, 1 warning, 0
+// failed."
+func checkTally(checks []Check) string {
+	var counts [3]int
+	for _, c := range checks {
+		counts[c.Status]++
+	}
+	return fmt.Sprintf("%d passed, %s, %d failed.", counts[CheckPass], Plural(counts[CheckWarn], "warning"), counts[CheckFail])
+}
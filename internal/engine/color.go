@@ -1,35 +1,67 @@
 package engine
 
-// ANSI escape codes for terminal coloring.
-// Applied unconditionally. Modern terminals universally support them.
-const (
-	colorReset  = "\033[0m"
-	colorBold   = "\033[1m"
-	colorDim    = "\033[2m"
-	colorItalic = "\033[3m"
+import "image/color"
 
-	colorBrightRed    = "\033[91m"
-	colorBrightGreen  = "\033[92m"
-	colorBrightYellow = "\033[93m"
-	colorBrightCyan   = "\033[96m"
+// ANSI escape codes for text attributes. Colors come from the active theme.
+const (
+	colorReset   = "\033[0m"
+	colorBold    = "\033[1m"
+	colorDim     = "\033[2m"
+	colorItalic  = "\033[3m"
+	colorReverse = "\033[7m"
 )
 
-func bold(s string) string       { return colorBold + s + colorReset }
-func dim(s string) string        { return colorDim + s + colorReset }
-func italic(s string) string     { return colorItalic + s + colorReset }
-func boldCyan(s string) string   { return colorBrightCyan + colorBold + s + colorReset }
-func boldYellow(s string) string { return colorBrightYellow + colorBold + s + colorReset }
-func dimCyan(s string) string    { return colorBrightCyan + colorDim + s + colorReset }
+func bold(s string) string      { return colorBold + s + colorReset }
+func dim(s string) string       { return colorDim + s + colorReset }
+func italic(s string) string    { return colorItalic + s + colorReset }
+func heading(s string) string   { return active.heading + colorBold + s + colorReset }
+func section(s string) string   { return active.section + colorBold + s + colorReset }
+func dimAccent(s string) string { return active.accent + colorDim + s + colorReset }
 
-// riskFillColor returns the ANSI color for the filled portion of the risk bar.
-// Green for calm, yellow for concerning, red for alarming.
+// riskFillColor returns the ANSI color for the filled portion of the risk bar:
+// the theme's calm, concerning or alarming color.
 func riskFillColor(score int) string {
 	switch {
-	case score >= 70:
-		return colorBrightRed
-	case score >= 40:
-		return colorBrightYellow
+	case score >= active.alarmingAt:
+		return active.alarming
+	case score >= active.concerningAt:
+		return active.concerning
+	default:
+		return active.calm
+	}
+}
+
+// Palette holds the RGB equivalents of the terminal colors, for output such
+// as image cards that cannot use ANSI escapes.
+var (
+	RGBRed    = color.RGBA{0xff, 0x55, 0x55, 0xff}
+	RGBYellow = color.RGBA{0xf1, 0xc4, 0x0f, 0xff}
+	RGBGreen  = color.RGBA{0x50, 0xfa, 0x7b, 0xff}
+	RGBCyan   = color.RGBA{0x8b, 0xe9, 0xfd, 0xff}
+)
+
+// RiskRGB is riskFillColor for images: green, yellow or red by the same
+// thresholds.
+func RiskRGB(score int) color.RGBA {
+	switch {
+	case score >= active.alarmingAt:
+		return RGBRed
+	case score >= active.concerningAt:
+		return RGBYellow
+	default:
+		return RGBGreen
+	}
+}
+
+// RiskLevel names the band a risk score falls in, using the same thresholds
+// as riskFillColor.
+func RiskLevel(score int) string {
+	switch {
+	case score >= active.alarmingAt:
+		return "alarming"
+	case score >= active.concerningAt:
+		return "concerning"
 	default:
-		return colorBrightGreen
+		return "calm"
 	}
 }
@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layout selects how PrintComparison arranges several results.
+type Layout string
+
+const (
+	// LayoutColumns renders condensed results side by side.
+	LayoutColumns Layout = "columns"
+	// LayoutPanels renders each full report one after another.
+	LayoutPanels Layout = "panels"
+)
+
+// columnGap is the number of spaces between adjacent comparison columns.
+const columnGap = 3
+
+// minColumnWidth is the narrowest column PrintComparison will attempt before
+// falling back to sequential panels.
+const minColumnWidth = 24
+
+// PrintComparison renders the runs of several thinkers against the same
+// question, followed by a summary table. width is the terminal width used to
+// size columns; if the columns would not fit, or in accessible mode, panels
+// are used instead.
+func (f *Formatter) PrintComparison(runs []Run, layout Layout, width int) {
+	if f.accessible {
+		f.printPanels(runs)
+		f.printSummaryAccessible(runs)
+		return
+	}
+	colWidth := 0
+	if len(runs) > 0 {
+		colWidth = (width - columnGap*(len(runs)-1)) / len(runs)
+	}
+	if layout == LayoutColumns && colWidth < minColumnWidth {
+		layout = LayoutPanels
+	}
+
+	switch layout {
+	case LayoutColumns:
+		f.printColumns(runs, colWidth)
+	default:
+		f.printPanels(runs)
+	}
+
+	f.printSummaryTable(runs)
+}
+
+func (f *Formatter) printPanels(runs []Run) {
+	for _, r := range runs {
+		f.PrintModelHeader(r.Name)
+		if r.Err != nil {
+			f.line("")
+			if f.accessible {
+				f.linef("Failed: %s.", r.Err)
+			} else {
+				f.linef("  %s", section("Failed: ")+r.Err.Error())
+			}
+			f.line("")
+			continue
+		}
+		f.Print(r.Result)
+	}
+}
+
+func (f *Formatter) printColumns(runs []Run, colWidth int) {
+	columns := make([][]string, len(runs))
+	height := 0
+	for i, r := range runs {
+		columns[i] = renderColumn(r, colWidth)
+		if len(columns[i]) > height {
+			height = len(columns[i])
+		}
+	}
+
+	f.line("")
+	gap := strings.Repeat(" ", columnGap)
+	for row := 0; row < height; row++ {
+		var sb strings.Builder
+		for i, col := range columns {
+			cell := ""
+			if row < len(col) {
+				cell = col[row]
+			}
+			if i < len(columns)-1 {
+				cell = padRight(cell, colWidth) + gap
+			}
+			sb.WriteString(cell)
+		}
+		f.line(strings.TrimRight(sb.String(), " "))
+	}
+	f.line("")
+}
+
+// renderColumn condenses a single run into lines no wider than width.
+func renderColumn(r Run, width int) []string {
+	lines := []string{
+		heading(truncate("[ "+r.Name+" ]", width)),
+		dim(RenderDivider(width)),
+	}
+	if r.Err != nil {
+		lines = append(lines, section("Failed:"))
+		for _, l := range wrapText(r.Err.Error(), width) {
+			lines = append(lines, dim(l))
+		}
+		return lines
+	}
+
+	res := r.Result
+	for _, l := range wrapText(res.Title, width) {
+		lines = append(lines, bold(l))
+	}
+	lines = append(lines, "")
+
+	fillColor := riskFillColor(res.RiskIndex)
+	lines = append(lines,
+		fmt.Sprintf("Risk %s%d%s/100", fillColor, res.RiskIndex, colorReset),
+		renderBar((res.RiskIndex*width)/100, width, fillColor),
+		"",
+	)
+
+	for _, p := range res.Probabilities {
+		pct := fmt.Sprintf("%5.1f%% ", p.Percentage)
+		label := wrapText(p.Label, width-len(pct))
+		for j, l := range label {
+			if j == 0 {
+				lines = append(lines, active.accent+pct+colorReset+dim(l))
+				continue
+			}
+			lines = append(lines, strings.Repeat(" ", len(pct))+dim(l))
+		}
+	}
+	lines = append(lines, "")
+
+	for _, l := range wrapText("--> "+res.ClosingLine, width) {
+		lines = append(lines, italic(l))
+	}
+	return lines
+}
+
+// printSummaryTable renders one row per run with its risk index, top
+// probability and latency.
+func (f *Formatter) printSummaryTable(runs []Run) {
+	nameWidth := len("Thinker")
+	for _, r := range runs {
+		if len(r.Name) > nameWidth {
+			nameWidth = len(r.Name)
+		}
+	}
+
+	f.linef("%s:", section("Comparison Summary"))
+	f.line("")
+	f.linef("  %s  %s  %s  %s",
+		bold(padRight("Thinker", nameWidth)),
+		bold(padRight("Risk", 6)),
+		bold(padRight("Latency", 9)),
+		bold("Top Probability"))
+	f.linef("  %s", dim(RenderDivider(nameWidth+6+9+40)))
+
+	for _, r := range runs {
+		latency := padRight(formatLatency(r.Latency), 9)
+		if r.Err != nil {
+			f.linef("  %s  %s  %s  %s",
+				padRight(r.Name, nameWidth),
+				padRight("--", 6),
+				latency,
+				section("failed")+"  "+dim(truncate(r.Err.Error(), 48)))
+			continue
+		}
+
+		risk := fmt.Sprintf("%s%3d%s", riskFillColor(r.Result.RiskIndex), r.Result.RiskIndex, colorReset)
+		top := "--"
+		if p, ok := r.TopProbability(); ok {
+			top = fmt.Sprintf("%5.1f%%  %s", p.Percentage, truncate(p.Label, 48))
+		}
+		f.linef("  %s  %s  %s  %s",
+			padRight(r.Name, nameWidth),
+			padRight(risk, 6),
+			latency,
+			top)
+	}
+	f.line("")
+}
+
+// formatLatency renders a duration with precision suited to its magnitude.
+func formatLatency(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// barFrames is the number of steps in which a dramatic bar fills.
+const barFrames = 20
+
+// Pacing sets the timing of the dramatic reveal used by Formatter.Print when
+// enabled with Formatter.SetDramatic.
+type Pacing struct {
+	// Char is the delay between characters of the typed title.
+	Char time.Duration
+	// Suspense is the pause before the risk index is revealed.
+	Suspense time.Duration
+	// BarFill is the time each bar takes to fill.
+	BarFill time.Duration
+	// Beat is the pause before the closing line.
+	Beat time.Duration
+	// Sleep waits for the given duration. It defaults to time.Sleep and can be
+	// replaced so the reveal runs instantly, e.g. in tests.
+	Sleep func(time.Duration)
+}
+
+// DefaultPacing returns the standard dramatic timing, scaled by speed: 2
+// reveals twice as fast, 0.5 takes twice as long. A speed of zero or less is
+// treated as 1.
+func DefaultPacing(speed float64) *Pacing {
+	if speed <= 0 {
+		speed = 1
+	}
+	scale := func(d time.Duration) time.Duration {
+		return time.Duration(float64(d) / speed)
+	}
+	return &Pacing{
+		Char:     scale(35 * time.Millisecond),
+		Suspense: scale(1200 * time.Millisecond),
+		BarFill:  scale(600 * time.Millisecond),
+		Beat:     scale(1500 * time.Millisecond),
+		Sleep:    time.Sleep,
+	}
+}
+
+// SetDramatic enables the dramatic reveal with the given pacing, or disables
+// it when p is nil. The reveal redraws lines with carriage returns, so it
+// should only be enabled when the output is a terminal.
+func (f *Formatter) SetDramatic(p *Pacing) {
+	f.pacing = p
+}
+
+func (f *Formatter) pause(d time.Duration) {
+	if f.pacing == nil || d <= 0 {
+		return
+	}
+	sleep := f.pacing.Sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	sleep(d)
+}
+
+// typeLine writes text one character at a time in the given color, followed
+// by a newline. Without pacing the line is written at once.
+func (f *Formatter) typeLine(indent, color, text string) {
+	if f.pacing == nil {
+		f.linef("%s%s%s%s", indent, color, text, colorReset)
+		return
+	}
+	fmt.Fprint(f.w, indent+color)
+	for _, r := range text {
+		fmt.Fprint(f.w, string(r))
+		f.pause(f.pacing.Char)
+	}
+	fmt.Fprintln(f.w, colorReset)
+}
+
+// fill draws output that grows to its final state: render is called with the
+// fill fraction in (0, 1] and each frame overwrites the previous one, moving
+// the cursor back up when a frame spans several lines. Without pacing only
+// the final frame is written.
+func (f *Formatter) fill(render func(fraction float64) string) {
+	if f.pacing == nil {
+		f.line(render(1))
+		return
+	}
+	step := f.pacing.BarFill / barFrames
+	for i := 1; i <= barFrames; i++ {
+		frame := render(float64(i) / barFrames)
+		fmt.Fprint(f.w, "\r"+frame)
+		if i == barFrames {
+			break
+		}
+		f.pause(step)
+		if n := strings.Count(frame, "\n"); n > 0 {
+			fmt.Fprintf(f.w, "\033[%dA", n)
+		}
+	}
+	fmt.Fprintln(f.w)
+}
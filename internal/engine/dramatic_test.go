@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Distinct durations, so each pause can be told apart.
+const (
+	testChar     = 1 * time.Millisecond
+	testSuspense = 2 * time.Second
+	testBarFill  = barFrames * 3 * time.Millisecond
+	testBeat     = 4 * time.Second
+)
+
+// pauseRecord is one call to the fake Sleep, with the output written so far.
+type pauseRecord struct {
+	d      time.Duration
+	output string
+}
+
+func recordingFormatter() (*Formatter, *bytes.Buffer, *[]pauseRecord) {
+	var buf bytes.Buffer
+	var pauses []pauseRecord
+	f := NewFormatter(&buf)
+	f.SetDramatic(&Pacing{
+		Char:     testChar,
+		Suspense: testSuspense,
+		BarFill:  testBarFill,
+		Beat:     testBeat,
+		Sleep: func(d time.Duration) {
+			pauses = append(pauses, pauseRecord{d: d, output: buf.String()})
+		},
+	})
+	return f, &buf, &pauses
+}
+
+func dramaticResult() *AnalysisResult {
+	return &AnalysisResult{
+		Title:   "THE TEST",
+		Summary: "Deeply concerning summary.",
+		Probabilities: []Probability{
+			{Label: "chance of passing", Percentage: 60},
+			{Label: "chance of failing", Percentage: 40},
+		},
+		RiskIndex:   72,
+		Citations:   []Citation{{Index: 1, Source: "Journal of Tests (2024)"}},
+		Conclusion:  "The end is near.",
+		ClosingLine: "Thank you for overthinking.",
+	}
+}
+
+func TestDramaticRevealOrder(t *testing.T) {
+	f, buf, pauses := recordingFormatter()
+	result := dramaticResult()
+	f.Print(result)
+
+	// Group consecutive pauses of the same length into phases.
+	var phases []time.Duration
+	var counts []int
+	for _, p := range *pauses {
+		if n := len(phases); n > 0 && phases[n-1] == p.d {
+			counts[n-1]++
+			continue
+		}
+		phases = append(phases, p.d)
+		counts = append(counts, 1)
+	}
+
+	step := testBarFill / barFrames
+	wantPhases := []time.Duration{testChar, step, testSuspense, step, testBeat}
+	if len(phases) != len(wantPhases) {
+		t.Fatalf("pause phases = %v, want %v", phases, wantPhases)
+	}
+	for i := range wantPhases {
+		if phases[i] != wantPhases[i] {
+			t.Fatalf("pause phases = %v, want %v", phases, wantPhases)
+		}
+	}
+	if counts[0] != len(result.Title) {
+		t.Errorf("title typed in %d pauses, want one per character (%d)", counts[0], len(result.Title))
+	}
+	// One fill per probability bar, then one for the risk index.
+	if want := len(result.Probabilities) * (barFrames - 1); counts[1] != want {
+		t.Errorf("probability bars paused %d times, want %d", counts[1], want)
+	}
+	if counts[3] != barFrames-1 {
+		t.Errorf("risk bar paused %d times, want %d", counts[3], barFrames-1)
+	}
+
+	at := func(d time.Duration) string {
+		for _, p := range *pauses {
+			if p.d == d {
+				return p.output
+			}
+		}
+		return ""
+	}
+	if first := at(testChar); !strings.HasSuffix(first, "T") || strings.Contains(first, result.Summary) {
+		t.Errorf("title not typed first: %q", first)
+	}
+	suspense := at(testSuspense)
+	if !strings.Contains(suspense, "chance of failing") || strings.Contains(suspense, DefaultHeadings.Risk) {
+		t.Error("risk index not held back until after the probabilities")
+	}
+	beat := at(testBeat)
+	if !strings.Contains(beat, result.Conclusion) || strings.Contains(beat, result.ClosingLine) {
+		t.Error("closing line not held back until after the conclusion")
+	}
+	if !strings.Contains(buf.String(), result.ClosingLine) {
+		t.Error("closing line never written")
+	}
+}
+
+func TestAccessibleOutputSkipsPacing(t *testing.T) {
+	f, buf, pauses := recordingFormatter()
+	f.SetAccessible(true)
+	f.Print(dramaticResult())
+	f.PrintBrief(dramaticResult())
+
+	if len(*pauses) != 0 {
+		t.Errorf("accessible output paused %d times, want none", len(*pauses))
+	}
+	if strings.Contains(buf.String(), "\r") {
+		t.Error("accessible output redraws lines")
+	}
+}
+
+func TestNoPacingWritesOnce(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFormatter(&buf)
+	f.SetDramatic(nil)
+	f.Print(dramaticResult())
+	if strings.Contains(buf.String(), "\r") {
+		t.Error("output without pacing redraws lines")
+	}
+}
@@ -3,60 +3,256 @@ package engine
 import (
 	"fmt"
 	"io"
+	"strings"
 )
 
 // Formatter handles all terminal output for the overthink engine.
 // It writes to an io.Writer, making it testable and redirectable.
 type Formatter struct {
-	w io.Writer
+	w          io.Writer
+	pacing     *Pacing
+	chart      Chart
+	accessible bool
+	ascii      bool
+	headings   Headings
 }
 
 // NewFormatter constructs a Formatter that writes to the given writer.
 func NewFormatter(w io.Writer) *Formatter {
-	return &Formatter{w: w}
+	return &Formatter{w: w, headings: DefaultHeadings}
+}
+
+// SetChart selects how Print draws the probability breakdown and the risk
+// index. The zero value draws bars.
+func (f *Formatter) SetChart(c Chart) {
+	f.chart = c
+}
+
+// SetASCII makes the pie, gauge and radar charts draw with 7-bit characters
+// only, for terminals that cannot display Unicode.
+func (f *Formatter) SetASCII(on bool) {
+	f.ascii = on
 }
 
 // Print renders a complete AnalysisResult in strict output order:
 //  1. DRAMATIC TITLE
-//  2. Divider line
+//  2. Divider line (plus a note if the backend's output had to be repaired)
 //  3. Executive Summary
 //  4. Probability Analysis (visual bars with percentages)
-//  5. Emotional Risk Index + ASCII bar
+//  5. Emotional Risk Index + ASCII bar (plus the change since the previous
+//     analysis in a conversation, and inter-rater disagreement when the
+//     result came from an ensemble)
 //  6. Academic Citations
 //  7. Grand Conclusion
 //  8. Closing Line
+//
+// The probabilities and the risk index are drawn as bars unless another
+// chart has been selected with SetChart. Sections carry the names set with
+// SetHeadings.
+//
+// In dramatic mode (see SetDramatic) the title is typed out, the risk index
+// is held back for a moment, the bars fill progressively and the closing
+// line arrives after a beat.
+//
+// In accessible mode (see SetAccessible) the same sections are written as
+// plain, labelled text instead.
 func (f *Formatter) Print(result *AnalysisResult) {
+	if f.accessible {
+		f.printAccessible(result)
+		return
+	}
 	f.line("")
-	f.linef("  %s", boldCyan(result.Title))
+	f.typeLine("  ", active.heading+colorBold, result.Title)
 	f.line(dim(RenderDivider(len(result.Title) + 2)))
+	if len(result.Repairs) > 0 {
+		f.linef("  %s", dim("Recovered: "+strings.Join(result.Repairs, "; ")))
+	}
 	f.line("")
-	f.section("Executive Summary", result.Summary)
+	f.section(f.headings.Summary, result.Summary)
 	f.line("")
 	f.printProbabilities(result.Probabilities)
 	f.line("")
-	fillColor := riskFillColor(result.RiskIndex)
-	f.line(RenderRiskBar(result.RiskIndex, fillColor))
+	if f.pacing != nil {
+		f.pause(f.pacing.Suspense)
+	}
+	f.fill(func(fraction float64) string {
+		score := int(float64(result.RiskIndex)*fraction + 0.5)
+		if f.chart == ChartGauge {
+			return renderRiskGauge(f.headings.Risk, score, f.ascii)
+		}
+		return renderRiskBar(f.headings.Risk, score, riskFillColor(score))
+	})
+	if f.chart == ChartRadar && len(result.RiskProfile) > 0 {
+		f.line("")
+		f.linef("%s:", section("Risk Profile"))
+		f.line(RenderRiskRadar(result.RiskProfile, f.ascii))
+	}
+	if result.PreviousRiskIndex != nil {
+		f.line(RenderRiskDelta(result.RiskIndex, *result.PreviousRiskIndex))
+	}
+	if result.Consensus != nil {
+		f.printConsensus(result.Consensus)
+	}
 	f.line("")
 	f.printCitations(result.Citations)
 	f.line("")
-	f.section("Grand Conclusion", result.Conclusion)
+	f.section(f.headings.Conclusion, result.Conclusion)
 	f.line("")
+	if f.pacing != nil {
+		f.pause(f.pacing.Beat)
+	}
 	f.linef("  %s", italic(bold("--> "+result.ClosingLine)))
 	f.line("")
 }
 
+// PrintBrief renders a condensed result: the title, the risk index, the most
+// likely outcome and the closing line.
+func (f *Formatter) PrintBrief(result *AnalysisResult) {
+	if f.accessible {
+		f.printBriefAccessible(result)
+		return
+	}
+	f.line("")
+	f.linef("  %s", heading(result.Title))
+	fillColor := riskFillColor(result.RiskIndex)
+	risk := fmt.Sprintf("%s%d%s/100", fillColor, result.RiskIndex, colorReset)
+	if result.PreviousRiskIndex != nil {
+		risk += "  " + RenderRiskDelta(result.RiskIndex, *result.PreviousRiskIndex)
+	}
+	f.linef("  %s %s", dim("Risk:"), risk)
+	if p, ok := (Run{Result: result}).TopProbability(); ok {
+		f.linef("  %s %s%.1f%%%s %s", dim("Most likely:"), active.accent, p.Percentage, colorReset, p.Label)
+	}
+	f.linef("  %s", italic(bold("--> "+result.ClosingLine)))
+	f.line("")
+}
+
+// PrintSessionSummary renders the statistics of an interactive session:
+// how many questions were asked, the average risk index, the most frequent
+// outcome label and the total number of citations fabricated.
+func (f *Formatter) PrintSessionSummary(t *Tally) {
+	if f.accessible {
+		f.printSessionSummaryAccessible(t)
+		return
+	}
+	f.line("")
+	f.linef("%s:", section("Session Summary"))
+	if t.Count == 0 {
+		f.linef("  %s", dim("No questions were overthought. Suspiciously well-adjusted."))
+		f.line("")
+		return
+	}
+	avg := t.AverageRisk()
+	f.linef("  %s %d", dim("Questions overthought:"), t.Count)
+	f.linef("  %s %s%.1f%s/100", dim("Average risk index:   "), riskFillColor(int(avg)), avg, colorReset)
+	if top := t.TopLabels(1); len(top) > 0 {
+		f.linef("  %s %s %s", dim("Most frequent outcome:"), top[0].Label, dim(fmt.Sprintf("(%dx)", top[0].Count)))
+	}
+	f.linef("  %s %d", dim("Citations fabricated: "), t.Citations)
+	f.line("")
+}
+
+// PrintHistory renders charts of past analyses: a sparkline of the risk
+// index in the order given, a histogram of the scores, and a column chart of
+// the top outcome labels in t. With ascii set, the charts use only 7-bit
+// characters.
+func (f *Formatter) PrintHistory(scores []int, t *Tally, top int, ascii bool) {
+	if f.accessible {
+		f.printHistoryAccessible(scores, t, top)
+		return
+	}
+	f.line("")
+	if len(scores) == 0 {
+		f.linef("%s:", section("Risk History"))
+		f.linef("  %s", dim("No analyses logged yet. Overthink something first."))
+		f.line("")
+		return
+	}
+
+	latest := scores[len(scores)-1]
+	f.linef("%s:", section("Risk History"))
+	f.linef("  %s  %s", RenderSparkline(scores, ascii), dim(fmt.Sprintf("(%d runs)", len(scores))))
+	summary := fmt.Sprintf("  %s %s%.1f%s/100   %s %s%d%s/100",
+		dim("Average:"), riskFillColor(int(t.AverageRisk())), t.AverageRisk(), colorReset,
+		dim("Latest:"), riskFillColor(latest), latest, colorReset)
+	if len(scores) > 1 {
+		summary += "  " + RenderRiskDelta(latest, scores[len(scores)-2])
+	}
+	f.line(summary)
+	f.line("")
+
+	f.linef("%s:", section("Risk Distribution"))
+	fmt.Fprint(f.w, RenderRiskHistogram(scores, ascii))
+	f.line("")
+
+	if labels := t.TopLabels(top); len(labels) > 0 {
+		f.linef("%s:", section("Most Common Outcomes"))
+		f.line("")
+		fmt.Fprint(f.w, RenderColumnChart(labels, historyChartHeight, ascii))
+		f.line("")
+	}
+}
+
+// historyChartHeight is the height, in rows, of the outcome column chart.
+const historyChartHeight = 8
+
 // PrintModelHeader renders the "[ Thinker: model ]" attribution header.
 // Call this before Print when displaying results from an LLM.
 func (f *Formatter) PrintModelHeader(model string) {
+	if f.accessible {
+		f.line("")
+		f.linef("Thinker: %s.", model)
+		return
+	}
 	f.line("")
-	f.linef("  %s", boldCyan("[ Thinker: "+model+" ]"))
+	f.linef("  %s", heading("[ Thinker: "+model+" ]"))
 	f.line(dim(RenderDivider(60)))
 }
 
+// PrintAttempts renders the trail of a fallback chain: every thinker tried,
+// which try it was, how long it took and why it failed. Nothing is printed
+// when the first attempt succeeded.
+func (f *Formatter) PrintAttempts(attempts []Attempt) {
+	if len(attempts) <= 1 {
+		return
+	}
+	if f.accessible {
+		f.printAttemptsAccessible(attempts)
+		return
+	}
+	nameWidth := 0
+	for _, a := range attempts {
+		if len(a.Thinker) > nameWidth {
+			nameWidth = len(a.Thinker)
+		}
+	}
+
+	f.line("")
+	f.linef("%s:", section("Attempt Trail"))
+	for _, a := range attempts {
+		mark, detail := active.calm+"\u2713"+colorReset, "ok"
+		if a.Err != nil {
+			mark, detail = active.alarming+"\u2717"+colorReset, a.Err.Error()
+		}
+		f.linef("  %s %s  %s  %s  %s",
+			mark,
+			padRight(a.Thinker, nameWidth),
+			dim(fmt.Sprintf("try %d", a.Try)),
+			dim(padRight(formatLatency(a.Latency), 7)),
+			detail)
+	}
+}
+
 // PrintWarning prints a formatted warning message.
 // Used when Ollama is unavailable and the engine falls back to local mode.
 func (f *Formatter) PrintWarning(msg string) {
-	f.linef("%s  Warning: %s", colorBrightYellow+colorBold, msg+colorReset)
+	if f.accessible {
+		f.linef("Warning: %s", msg)
+		f.line("Falling back to the built-in overthinking engine.")
+		f.line("")
+		return
+	}
+	f.linef("%s  Warning: %s", active.section+colorBold, msg+colorReset)
 	f.linef("   Falling back to the built-in overthinking engine.")
 	f.line("")
 }
@@ -64,20 +260,50 @@ func (f *Formatter) PrintWarning(msg string) {
 // --- Private rendering helpers -----------------------------------------------
 
 func (f *Formatter) section(heading, body string) {
-	f.linef("%s:", boldYellow(heading))
+	f.linef("%s:", section(heading))
 	f.linef("  %s", body)
 }
 
 func (f *Formatter) printProbabilities(probs []Probability) {
-	f.linef("%s:", boldYellow("Probability Analysis"))
+	f.linef("%s:", section(f.headings.Probabilities))
+	f.line("")
+	if f.chart == ChartPie {
+		f.fill(func(fraction float64) string {
+			shown := make([]Probability, len(probs))
+			for i, p := range probs {
+				shown[i] = Probability{Label: p.Label, Percentage: p.Percentage * fraction}
+			}
+			return RenderProbabilityPie(shown, f.ascii)
+		})
+		f.line("")
+		return
+	}
+	for _, p := range probs {
+		f.fill(func(fraction float64) string {
+			shown := p
+			shown.Percentage *= fraction
+			return renderProbabilityBar(shown, active.accent)
+		})
+	}
 	f.line("")
-	f.line(RenderProbabilityBars(probs, colorBrightCyan))
 }
 
 func (f *Formatter) printCitations(citations []Citation) {
-	f.linef("%s:", boldYellow("Academic Citations"))
+	f.linef("%s:", section(f.headings.Citations))
 	for _, c := range citations {
-		f.linef("  %s  %s", dimCyan(fmt.Sprintf("[%d]", c.Index)), c.Source)
+		f.linef("  %s  %s", dimAccent(fmt.Sprintf("[%d]", c.Index)), c.Source)
+	}
+}
+
+func (f *Formatter) printConsensus(c *Consensus) {
+	scores := make([]string, len(c.Members))
+	for i, m := range c.Members {
+		scores[i] = fmt.Sprintf("%s %d", m, c.RiskScores[i])
+	}
+	f.linef("%s %s", dim("Inter-rater disagreement:"), fmt.Sprintf("\u00b1%.1f", c.Disagreement))
+	f.linef("%s %s", dim("Panel:"), dim(strings.Join(scores, ", ")))
+	for _, failure := range c.Failures {
+		f.linef("%s %s", dim("Absent:"), dim(failure))
 	}
 }
 
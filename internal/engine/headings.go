@@ -0,0 +1,42 @@
+package engine
+
+// Headings are the section names of a report, which a persona may rename.
+type Headings struct {
+	Summary       string `json:"summary,omitempty"`
+	Probabilities string `json:"probabilities,omitempty"`
+	Risk          string `json:"risk,omitempty"`
+	Citations     string `json:"citations,omitempty"`
+	Conclusion    string `json:"conclusion,omitempty"`
+}
+
+// DefaultHeadings are the section names of the standard report.
+var DefaultHeadings = Headings{
+	Summary:       "Executive Summary",
+	Probabilities: "Probability Analysis",
+	Risk:          "Emotional Risk Index",
+	Citations:     "Academic Citations",
+	Conclusion:    "Grand Conclusion",
+}
+
+// orDefault returns h with every empty heading taken from DefaultHeadings.
+func (h Headings) orDefault() Headings {
+	pick := func(s, fallback string) string {
+		if s == "" {
+			return fallback
+		}
+		return s
+	}
+	return Headings{
+		Summary:       pick(h.Summary, DefaultHeadings.Summary),
+		Probabilities: pick(h.Probabilities, DefaultHeadings.Probabilities),
+		Risk:          pick(h.Risk, DefaultHeadings.Risk),
+		Citations:     pick(h.Citations, DefaultHeadings.Citations),
+		Conclusion:    pick(h.Conclusion, DefaultHeadings.Conclusion),
+	}
+}
+
+// SetHeadings renames the sections Print writes. Empty headings keep their
+// standard names.
+func (f *Formatter) SetHeadings(h Headings) {
+	f.headings = h.orDefault()
+}
@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Intensity is how dramatic an analysis is, from 1 (mildly concerned) to 5
+// (full Greek tragedy). The zero value stands for DefaultIntensity.
+type Intensity int
+
+// Intensity bounds and default.
+const (
+	MinIntensity     Intensity = 1
+	DefaultIntensity Intensity = 3
+	MaxIntensity     Intensity = 5
+)
+
+// intensityNames describe each level, from MinIntensity up.
+var intensityNames = []string{
+	"mildly concerned",
+	"visibly uneasy",
+	"dramatic",
+	"operatic",
+	"full Greek tragedy",
+}
+
+// Level returns i as a valid level: DefaultIntensity for the zero value, and
+// otherwise i clamped to MinIntensity..MaxIntensity.
+func (i Intensity) Level() Intensity {
+	if i == 0 {
+		return DefaultIntensity
+	}
+	return max(MinIntensity, min(i, MaxIntensity))
+}
+
+// String returns the level's description, e.g. "full Greek tragedy".
+func (i Intensity) String() string {
+	return intensityNames[i.Level()-MinIntensity]
+}
+
+// ParseIntensity parses a level given as a number from 1 to 5.
+func ParseIntensity(s string) (Intensity, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || Intensity(n) < MinIntensity || Intensity(n) > MaxIntensity {
+		return 0, fmt.Errorf("invalid intensity %q (want %d to %d)", s, MinIntensity, MaxIntensity)
+	}
+	return Intensity(n), nil
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderMarkdown renders a result as a Markdown document with the same
+// sections, in the same order, as Formatter.Print. It contains no ANSI
+// escapes, so it can be pasted into chats, issues and newsletters.
+func RenderMarkdown(r *AnalysisResult) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", r.Title)
+
+	fmt.Fprintf(&sb, "## Executive Summary\n\n%s\n\n", r.Summary)
+
+	sb.WriteString("## Probability Analysis\n\n")
+	sb.WriteString("| Probability | Outcome |\n|---:|---|\n")
+	for _, p := range r.Probabilities {
+		fmt.Fprintf(&sb, "| %.1f%% | %s |\n", p.Percentage, p.Label)
+	}
+	sb.WriteString("\n")
+
+	fmt.Fprintf(&sb, "## Emotional Risk Index\n\n**%d/100** (%s)\n\n", r.RiskIndex, RiskLevel(r.RiskIndex))
+
+	sb.WriteString("## Academic Citations\n\n")
+	for _, c := range r.Citations {
+		fmt.Fprintf(&sb, "%d. %s\n", c.Index, c.Source)
+	}
+	sb.WriteString("\n")
+
+	fmt.Fprintf(&sb, "## Grand Conclusion\n\n%s\n\n", r.Conclusion)
+	fmt.Fprintf(&sb, "> *%s*\n", r.ClosingLine)
+	return sb.String()
+}
@@ -0,0 +1,347 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Chart selects how Formatter.Print draws the probability breakdown and the
+// risk index.
+type Chart string
+
+// Available chart styles.
+const (
+	// ChartBar draws horizontal bars for both; it is the default.
+	ChartBar Chart = "bar"
+	// ChartPie draws the probability breakdown as a donut.
+	ChartPie Chart = "pie"
+	// ChartGauge draws the risk index as a semicircular gauge.
+	ChartGauge Chart = "gauge"
+	// ChartRadar adds a radar chart of the risk profile below the risk bar.
+	ChartRadar Chart = "radar"
+)
+
+// Charts lists every chart style, in the order shown in help text.
+var Charts = []Chart{ChartBar, ChartPie, ChartGauge, ChartRadar}
+
+// Plot sizes. A braille dot is as tall as it is wide: two dots span a
+// column and four a row.
+const (
+	// pieRadius is the outer radius of the donut, in rows.
+	pieRadius = 6
+	// gaugeRadius is the radius of the gauge, in braille dots.
+	gaugeRadius = 32
+	// radarRadius is the radius of the radar chart, in braille dots.
+	radarRadius = 24
+	// radarMargin is the room, in columns, left on each side of the radar
+	// for labels.
+	radarMargin = 16
+)
+
+// asciiSliceGlyphs tell the slices of an ASCII pie chart apart without
+// relying on color.
+var asciiSliceGlyphs = []string{"#", "@", "%", "&", "+"}
+
+// RenderProbabilityPie renders the probability breakdown as a donut drawn
+// with block characters, with a legend to its right. The part of the ring not
+// covered by the probabilities, if any, is dimmed, so a breakdown scaled down
+// for an animation appears to sweep round. With ascii set, each slice is drawn
+// with its own 7-bit character instead.
+func RenderProbabilityPie(probs []Probability, ascii bool) string {
+	height := 2 * pieRadius
+	width := 2 * height
+	inner := float64(pieRadius) * 0.5
+
+	bounds := make([]float64, len(probs))
+	total := 0.0
+	for i, p := range probs {
+		total += p.Percentage
+		bounds[i] = total
+	}
+
+	lines := make([]string, height)
+	for row := 0; row < height; row++ {
+		var sb strings.Builder
+		for col := 0; col < width; col++ {
+			// Cells are twice as tall as they are wide.
+			dx := (float64(col) + 0.5 - float64(width)/2) / 2
+			dy := float64(row) + 0.5 - float64(height)/2
+			dist := math.Hypot(dx, dy)
+			if dist > float64(pieRadius) || dist < inner {
+				sb.WriteString(" ")
+				continue
+			}
+			// Clockwise from twelve o'clock, as a percentage of the circle.
+			angle := math.Atan2(dx, -dy)
+			if angle < 0 {
+				angle += 2 * math.Pi
+			}
+			pct := angle / (2 * math.Pi) * 100
+			slice := -1
+			for i, b := range bounds {
+				if pct < b {
+					slice = i
+					break
+				}
+			}
+			if slice < 0 {
+				sb.WriteString(dim(emptyGlyph(ascii)))
+				continue
+			}
+			sb.WriteString(sliceColor(slice) + sliceGlyph(slice, ascii) + colorReset)
+		}
+		lines[row] = sb.String()
+	}
+
+	// Centre the legend vertically beside the donut.
+	top := (height - len(probs)) / 2
+	for i, p := range probs {
+		row := top + i
+		if row < 0 || row >= height {
+			continue
+		}
+		lines[row] += fmt.Sprintf("   %s %5.1f%%  %s",
+			sliceColor(i)+sliceGlyph(i, ascii)+colorReset, p.Percentage, dim(p.Label))
+	}
+	return "  " + strings.Join(lines, "\n  ")
+}
+
+// sliceColor returns the color of the i-th slice of a pie chart.
+func sliceColor(i int) string {
+	return active.slices[i%len(active.slices)]
+}
+
+// sliceGlyph returns the glyph slice i of a pie chart is drawn with.
+func sliceGlyph(i int, ascii bool) string {
+	if ascii {
+		return asciiSliceGlyphs[i%len(asciiSliceGlyphs)]
+	}
+	return active.filled
+}
+
+// emptyGlyph returns the glyph of the part of a pie chart no slice covers.
+func emptyGlyph(ascii bool) string {
+	if ascii {
+		return asciiEmpty
+	}
+	return active.empty
+}
+
+// RenderRiskGauge renders the Emotional Risk Index as a semicircular gauge
+// drawn with braille dots. The arc runs from calm on the left to alarming on
+// the right, colored with the same thresholds as the risk bar, and is lit up
+// to score; a needle points at the score. With ascii set, the dots are
+// approximated with 7-bit characters.
+func RenderRiskGauge(score int, ascii bool) string {
+	return renderRiskGauge(DefaultHeadings.Risk, score, ascii)
+}
+
+// renderRiskGauge is RenderRiskGauge with the label's name given.
+func renderRiskGauge(name string, score int, ascii bool) string {
+	score = clampScore(score)
+	r := float64(gaugeRadius)
+	thickness := r / 6
+	c := newBrailleCanvas(gaugeRadius+1, gaugeRadius/4)
+	cx, cy := r, float64(c.dotRows()-1)
+
+	for y := 0; y < c.dotRows(); y++ {
+		for x := 0; x < c.dotCols(); x++ {
+			dx, dy := float64(x)-cx, cy-float64(y)
+			dist := math.Hypot(dx, dy)
+			if dy < 0 || dist > r-0.5 || dist < r-thickness {
+				continue
+			}
+			value := int(100 * (1 - math.Atan2(dy, dx)/math.Pi))
+			color := colorDim
+			if value <= score {
+				color = riskFillColor(value)
+			}
+			c.set(x, y, color)
+		}
+	}
+
+	theta := math.Pi * (1 - float64(score)/100)
+	needle := r - thickness - 2
+	c.line(int(cx), int(cy), int(cx+needle*math.Cos(theta)), int(cy-needle*math.Sin(theta)), colorBold)
+
+	fillColor := riskFillColor(score)
+	label := fmt.Sprintf("%s%s: %s%d%s/100%s",
+		colorBold, name, fillColor, score, colorReset+colorBold, colorReset)
+	caption := fmt.Sprintf("%d/100 %s", score, RiskLevel(score))
+	pad := strings.Repeat(" ", 2+max(0, (c.cols-len(caption))/2))
+	return label + "\n" + c.render(ascii) + "\n" + pad + fillColor + caption + colorReset
+}
+
+// RenderRiskRadar renders a risk profile as a radar chart drawn with braille
+// dots: one axis per category, starting at twelve o'clock and running
+// clockwise, with the profile's outline colored by its average score. With
+// ascii set, the dots are approximated with 7-bit characters.
+func RenderRiskRadar(profile []RiskCategory, ascii bool) string {
+	n := len(profile)
+	if n < 3 {
+		return ""
+	}
+	r := float64(radarRadius)
+	c := newBrailleCanvas(radarRadius+2*radarMargin+1, radarRadius/2+2)
+	cx, cy := float64(c.dotCols()/2), float64(c.dotRows()/2)
+
+	point := func(i int, radius float64) (int, int) {
+		theta := -math.Pi/2 + 2*math.Pi*float64(i)/float64(n)
+		return int(math.Round(cx + radius*math.Cos(theta))), int(math.Round(cy + radius*math.Sin(theta)))
+	}
+
+	total := 0
+	for _, cat := range profile {
+		total += clampScore(cat.Score)
+	}
+	outline := riskFillColor(total / n)
+
+	for i := range profile {
+		x0, y0 := point(i, r)
+		x1, y1 := point((i+1)%n, r)
+		c.line(int(cx), int(cy), x0, y0, colorDim)
+		c.line(x0, y0, x1, y1, colorDim)
+	}
+	for i, cat := range profile {
+		x0, y0 := point(i, r*float64(clampScore(cat.Score))/100)
+		next := profile[(i+1)%n]
+		x1, y1 := point((i+1)%n, r*float64(clampScore(next.Score))/100)
+		c.line(x0, y0, x1, y1, outline)
+	}
+
+	for i, cat := range profile {
+		x, y := point(i, r+4)
+		col, row := x/2, y/4
+		text := fmt.Sprintf("%s %d", cat.Name, cat.Score)
+		switch cos := math.Cos(-math.Pi/2 + 2*math.Pi*float64(i)/float64(n)); {
+		case cos > 0.3:
+			col++
+		case cos < -0.3:
+			col -= len(text)
+		default:
+			col -= len(text) / 2
+		}
+		c.label(col, row, text, riskFillColor(cat.Score))
+	}
+	return c.render(ascii)
+}
+
+// brailleCanvas is a grid of terminal cells, each holding a 2x4 block of
+// braille dots or a single character of text. Braille dots are roughly
+// square, which makes them well suited to drawing circles and lines.
+type brailleCanvas struct {
+	cols, rows int
+	dots       [][]rune
+	colors     [][]string
+	text       [][]rune
+}
+
+// brailleBits maps a dot's position within its cell to its bit in the
+// braille pattern, indexed by [y][x].
+var brailleBits = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+func newBrailleCanvas(cols, rows int) *brailleCanvas {
+	c := &brailleCanvas{cols: cols, rows: rows}
+	c.dots = make([][]rune, rows)
+	c.colors = make([][]string, rows)
+	c.text = make([][]rune, rows)
+	for i := range c.dots {
+		c.dots[i] = make([]rune, cols)
+		c.colors[i] = make([]string, cols)
+		c.text[i] = make([]rune, cols)
+	}
+	return c
+}
+
+func (c *brailleCanvas) dotCols() int { return 2 * c.cols }
+func (c *brailleCanvas) dotRows() int { return 4 * c.rows }
+
+// set lights the dot at (x, y) in color, which becomes the color of the
+// whole cell. Dots outside the canvas are ignored.
+func (c *brailleCanvas) set(x, y int, color string) {
+	if x < 0 || y < 0 || x >= c.dotCols() || y >= c.dotRows() {
+		return
+	}
+	c.dots[y/4][x/2] |= brailleBits[y%4][x%2]
+	c.colors[y/4][x/2] = color
+}
+
+// line draws a straight line of dots from (x0, y0) to (x1, y1).
+func (c *brailleCanvas) line(x0, y0, x1, y1 int, color string) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		c.set(x0, y0, color)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// label writes text into the cells starting at (col, row), in front of any
+// dots, shifted as needed to stay within the canvas.
+func (c *brailleCanvas) label(col, row int, text, color string) {
+	runes := []rune(text)
+	row = max(0, min(row, c.rows-1))
+	col = max(0, min(col, c.cols-len(runes)))
+	for i, r := range runes {
+		if col+i < c.cols {
+			c.text[row][col+i] = r
+			c.colors[row][col+i] = color
+		}
+	}
+}
+
+// render draws the canvas, one line per row of cells, without trailing blank
+// lines. With ascii set, a cell holding dots is drawn as asciiEmpty when dim
+// and asciiFilled otherwise, rather than as a braille pattern.
+func (c *brailleCanvas) render(ascii bool) string {
+	lines := make([]string, c.rows)
+	for row := range lines {
+		var sb strings.Builder
+		for col := 0; col < c.cols; col++ {
+			switch {
+			case c.text[row][col] != 0:
+				sb.WriteString(c.colors[row][col] + string(c.text[row][col]) + colorReset)
+			case c.dots[row][col] != 0 && ascii:
+				glyph := asciiFilled
+				if c.colors[row][col] == colorDim {
+					glyph = asciiEmpty
+				}
+				sb.WriteString(c.colors[row][col] + glyph + colorReset)
+			case c.dots[row][col] != 0:
+				sb.WriteString(c.colors[row][col] + string(0x2800+c.dots[row][col]) + colorReset)
+			default:
+				sb.WriteByte(' ')
+			}
+		}
+		if line := strings.TrimRight(sb.String(), " "); line != "" {
+			lines[row] = "  " + line
+		}
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
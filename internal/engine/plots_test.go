@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// ansi matches the escape sequences that color the charts. Golden files hold
+// the shapes only, so that they stay readable in a diff.
+var ansi = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// golden compares got, stripped of color, with testdata/plots/<name>.golden,
+// or rewrites the file when -update is set.
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	got = ansi.ReplaceAllString(got, "") + "\n"
+	path := filepath.Join("testdata", "plots", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s does not match:\n got:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// glyphs names the golden file for each rendering path.
+var glyphs = []struct {
+	name  string
+	ascii bool
+}{
+	{"unicode", false},
+	{"ascii", true},
+}
+
+func TestRenderProbabilityPie(t *testing.T) {
+	probs := []Probability{
+		{Label: "chance of regret", Percentage: 45},
+		{Label: "chance of relief", Percentage: 30},
+		{Label: "chance of a sequel", Percentage: 25},
+	}
+	partial := []Probability{{Label: "chance of regret", Percentage: 40}}
+	for _, g := range glyphs {
+		golden(t, "pie_"+g.name, RenderProbabilityPie(probs, g.ascii))
+		golden(t, "pie_partial_"+g.name, RenderProbabilityPie(partial, g.ascii))
+	}
+}
+
+func TestRenderRiskGauge(t *testing.T) {
+	for _, g := range glyphs {
+		for _, score := range []struct {
+			name  string
+			score int
+		}{{"0", 0}, {"50", 50}, {"100", 100}} {
+			golden(t, "gauge_"+score.name+"_"+g.name, RenderRiskGauge(score.score, g.ascii))
+		}
+	}
+}
+
+func TestRenderRiskRadar(t *testing.T) {
+	profile := []RiskCategory{
+		{Name: "romantic", Score: 80},
+		{Name: "professional", Score: 35},
+		{Name: "existential", Score: 95},
+		{Name: "financial", Score: 20},
+		{Name: "social", Score: 60},
+	}
+	for _, g := range glyphs {
+		golden(t, "radar_"+g.name, RenderRiskRadar(profile, g.ascii))
+	}
+	if got := RenderRiskRadar(profile[:2], false); got != "" {
+		t.Errorf("radar of two categories = %q, want nothing", got)
+	}
+}
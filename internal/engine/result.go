@@ -1,28 +1,78 @@
 package engine
 
+import "time"
+
 // AnalysisResult is the complete output produced by any Thinker implementation.
-// All fields are populated before being handed to the Formatter.
+// All fields are populated before being handed to the Formatter. The JSON
+// form is used by machine-readable output such as batch mode.
 type AnalysisResult struct {
-	Title         string
-	Summary       string
-	Probabilities []Probability
-	RiskIndex     int
-	Citations     []Citation
-	Conclusion    string
-	ClosingLine   string
+	Title         string        `json:"title"`
+	Summary       string        `json:"summary"`
+	Probabilities []Probability `json:"probabilities"`
+	RiskIndex     int           `json:"risk_index"`
+	Citations     []Citation    `json:"citations"`
+	Conclusion    string        `json:"conclusion"`
+	ClosingLine   string        `json:"closing_line"`
+
+	// RiskProfile breaks the risk down by theme, for the radar chart. It is
+	// set by the local engine and may be absent from other thinkers' results.
+	RiskProfile []RiskCategory `json:"risk_profile,omitempty"`
+
+	// PreviousRiskIndex is the risk index of the analysis this one revises,
+	// set for follow-up questions in a conversation.
+	PreviousRiskIndex *int `json:"previous_risk_index,omitempty"`
+
+	// Consensus is set when the result was merged from several thinkers.
+	Consensus *Consensus `json:"consensus,omitempty"`
+	// Attempts is the trail of thinkers tried before this result was
+	// produced, set when the result came from a fallback chain.
+	Attempts []Attempt `json:"-"`
+	// Repairs lists the recovery steps applied to a malformed backend
+	// response, such as repaired JSON or fields supplied by another engine.
+	Repairs []string `json:"repairs,omitempty"`
 }
 
 // Probability represents a single entry in the pseudo-statistical breakdown.
 // The Label describes the outcome; Percentage is a suspiciously precise number.
 type Probability struct {
-	Label      string
-	Percentage float64
+	Label      string  `json:"label"`
+	Percentage float64 `json:"percentage"`
 }
 
 // Citation represents a single fabricated academic reference.
 // All citations are entirely fictional. Any resemblance to real journals
 // is a symptom of academic overexposure.
 type Citation struct {
-	Index  int
-	Source string
+	Index  int    `json:"index"`
+	Source string `json:"source"`
+}
+
+// RiskCategory is the risk score (0-100) of one theme, such as "romantic"
+// or "financial".
+type RiskCategory struct {
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+// Consensus describes how an ensemble of thinkers arrived at a merged result.
+// RiskIndex on the parent AnalysisResult is the mean of RiskScores.
+type Consensus struct {
+	// Members lists the thinkers that contributed, in the order given.
+	Members []string `json:"members"`
+	// RiskScores holds each contributing member's risk index.
+	RiskScores []int `json:"risk_scores"`
+	// Disagreement is the standard deviation of RiskScores, reported to the
+	// user as inter-rater disagreement.
+	Disagreement float64 `json:"disagreement"`
+	// Failures describes members that did not produce a result.
+	Failures []string `json:"failures,omitempty"`
+}
+
+// Attempt records one try of one thinker within a fallback chain.
+// Err is nil for the attempt that produced the result.
+type Attempt struct {
+	Thinker string
+	Try     int
+	Err     error
+	Latency time.Duration
 }
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrTimeout is returned when a Thinker does not answer within its time budget.
+var ErrTimeout = errors.New("thinker timed out")
+
+// NamedThinker pairs a Thinker with the name it was selected by on the
+// command line (e.g. "llama3", "local"). The name is used in summaries.
+type NamedThinker struct {
+	Name    string
+	Thinker Thinker
+}
+
+// Run records the outcome of a single Thinker invocation.
+// Exactly one of Result and Err is set.
+type Run struct {
+	Name    string
+	Result  *AnalysisResult
+	Err     error
+	Latency time.Duration
+}
+
+// TopProbability returns the highest-percentage entry of the run's result,
+// or false if the run failed or produced no probabilities.
+func (r Run) TopProbability() (Probability, bool) {
+	if r.Result == nil || len(r.Result.Probabilities) == 0 {
+		return Probability{}, false
+	}
+	top := r.Result.Probabilities[0]
+	for _, p := range r.Result.Probabilities[1:] {
+		if p.Percentage > top.Percentage {
+			top = p
+		}
+	}
+	return top, true
+}
+
+// AnalyzeWithTimeout runs t under ctx and gives up after timeout. A
+// non-positive timeout waits for as long as ctx allows. A ContextThinker is
+// cancelled when it runs out of time; any other Thinker keeps running in the
+// background, and its result is discarded.
+func AnalyzeWithTimeout(ctx context.Context, t Thinker, question string, timeout time.Duration) (*AnalysisResult, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if ct, ok := t.(ContextThinker); ok {
+		result, err := ct.AnalyzeContext(ctx, question)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrTimeout, timeout)
+		}
+		return result, err
+	}
+
+	type outcome struct {
+		result *AnalysisResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := t.Analyze(question)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrTimeout, timeout)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// AnalyzeAll runs every thinker concurrently against the same question, each
+// with its own timeout. Runs are returned in the same order as thinkers.
+func AnalyzeAll(ctx context.Context, thinkers []NamedThinker, question string, timeout time.Duration) []Run {
+	runs := make([]Run, len(thinkers))
+	var wg sync.WaitGroup
+	for i, nt := range thinkers {
+		wg.Add(1)
+		go func(i int, nt NamedThinker) {
+			defer wg.Done()
+			start := time.Now()
+			result, err := AnalyzeWithTimeout(ctx, nt.Thinker, question, timeout)
+			runs[i] = Run{
+				Name:    nt.Name,
+				Result:  result,
+				Err:     err,
+				Latency: time.Since(start),
+			}
+		}(i, nt)
+	}
+	wg.Wait()
+	return runs
+}
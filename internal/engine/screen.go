@@ -0,0 +1,245 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// revealFrames is the number of animation frames over which the bars fill
+// and the risk index counts up.
+const revealFrames = 36
+
+// spinnerFrames animate the loading indicator while a thinker is working.
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// Screen is the full-screen, animated presentation of an AnalysisResult used
+// by the terminal UI. It is a pure model: callers feed it ticks and key
+// actions and draw whatever Render returns.
+type Screen struct {
+	// Result is the analysis being shown, or nil while one is in progress.
+	Result *AnalysisResult
+	// Thinker names the thinker that produced (or is producing) Result.
+	Thinker string
+	// Status is a transient message shown in the footer.
+	Status string
+	// Prompt, when non-empty, replaces the key help in the footer; it is used
+	// for inline input such as choosing a new thinker.
+	Prompt string
+
+	frame     int
+	focus     int
+	collapsed map[int]bool
+}
+
+// screenSections are the collapsible sections, in display order.
+var screenSections = []string{
+	"Executive Summary",
+	"Probability Analysis",
+	"Emotional Risk Index",
+	"Academic Citations",
+	"Grand Conclusion",
+}
+
+// NewScreen constructs an empty Screen for the named thinker.
+func NewScreen(thinker string) *Screen {
+	return &Screen{Thinker: thinker, collapsed: make(map[int]bool)}
+}
+
+// Show replaces the displayed result and restarts the reveal animation.
+// Collapsed sections stay collapsed.
+func (s *Screen) Show(result *AnalysisResult) {
+	s.Result = result
+	s.frame = 0
+}
+
+// Tick advances the animation by one frame. It reports whether anything on
+// screen changed, so callers can skip redundant redraws.
+func (s *Screen) Tick() bool {
+	if s.Result != nil && s.frame >= revealFrames {
+		return false
+	}
+	s.frame++
+	return true
+}
+
+// Animating reports whether the reveal animation is still running.
+func (s *Screen) Animating() bool {
+	return s.Result == nil || s.frame < revealFrames
+}
+
+// Skip jumps to the end of the reveal animation.
+func (s *Screen) Skip() {
+	if s.Result != nil {
+		s.frame = revealFrames
+	}
+}
+
+// FocusNext and FocusPrev move the section cursor.
+func (s *Screen) FocusNext() { s.focus = (s.focus + 1) % len(screenSections) }
+func (s *Screen) FocusPrev() {
+	s.focus = (s.focus + len(screenSections) - 1) % len(screenSections)
+}
+
+// Toggle collapses or expands the focused section.
+func (s *Screen) Toggle() { s.collapsed[s.focus] = !s.collapsed[s.focus] }
+
+// progress returns the eased reveal progress in [0, 1].
+func (s *Screen) progress() float64 {
+	p := float64(s.frame) / revealFrames
+	if p > 1 {
+		p = 1
+	}
+	return 1 - math.Pow(1-p, 3)
+}
+
+// Render draws the whole screen for a terminal of the given size. Lines are
+// separated by "\r\n" so the output is correct in raw mode.
+func (s *Screen) Render(width, height int) string {
+	if width < 20 {
+		width = 20
+	}
+	if height < 5 {
+		height = 5
+	}
+
+	header := []string{
+		padRight(heading(truncate("[ Thinker: "+s.Thinker+" ]", width)), width),
+		dim(RenderDivider(width)),
+	}
+
+	var body []string
+	focusLine := 0
+	if s.Result == nil {
+		spin := spinnerFrames[s.frame%len(spinnerFrames)]
+		body = append(body, "", "  "+section(spin)+" Overthinking with "+s.Thinker+"...")
+	} else {
+		body, focusLine = s.renderBody(width)
+	}
+
+	footer := s.renderFooter(width)
+
+	// Scroll so the focused section heading stays in view.
+	view := height - len(header) - 1
+	offset := 0
+	if focusLine >= view {
+		offset = focusLine - view/3
+	}
+	if max := len(body) - view; offset > max {
+		offset = max
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	body = body[offset:]
+	if len(body) > view {
+		body = body[:view]
+	}
+	for len(body) < view {
+		body = append(body, "")
+	}
+
+	lines := append(append(header, body...), footer)
+	return strings.Join(lines, "\r\n")
+}
+
+// renderBody renders the title and sections, returning the lines and the
+// index of the focused section's heading.
+func (s *Screen) renderBody(width int) ([]string, int) {
+	r := s.Result
+	progress := s.progress()
+	textWidth := width - 4
+
+	var lines []string
+	lines = append(lines, "")
+	for _, l := range wrapText(r.Title, width-2) {
+		lines = append(lines, "  "+heading(l))
+	}
+	if len(r.Repairs) > 0 {
+		lines = append(lines, "  "+dim(truncate("Recovered: "+strings.Join(r.Repairs, "; "), width-2)))
+	}
+	lines = append(lines, "")
+
+	focusLine := 0
+	for i, heading := range screenSections {
+		marker := "▾"
+		if s.collapsed[i] {
+			marker = "▸"
+		}
+		line := marker + " " + section(heading)
+		if i == s.focus {
+			focusLine = len(lines)
+			line = colorReverse + marker + colorReset + " " + section(heading)
+		}
+		lines = append(lines, line)
+		if s.collapsed[i] {
+			continue
+		}
+
+		switch i {
+		case 0:
+			for _, l := range wrapText(r.Summary, textWidth) {
+				lines = append(lines, "  "+l)
+			}
+		case 1:
+			barWidth := chartWidth
+			if width-30 < barWidth {
+				barWidth = width - 30
+			}
+			if barWidth < 5 {
+				barWidth = 5
+			}
+			for _, p := range r.Probabilities {
+				shown := p.Percentage * progress
+				filled := int((shown / 100.0) * float64(barWidth))
+				lines = append(lines, fmt.Sprintf("  %s%5.1f%%%s  %s  %s",
+					active.accent, shown, colorReset,
+					renderBar(filled, barWidth, active.accent),
+					dim(truncate(p.Label, width-barWidth-14))))
+			}
+		case 2:
+			shown := int(math.Round(float64(r.RiskIndex) * progress))
+			fillColor := riskFillColor(shown)
+			barWidth := chartWidth
+			if width-4 < barWidth {
+				barWidth = width - 4
+			}
+			lines = append(lines,
+				fmt.Sprintf("  %s%d%s/100  %s", fillColor+colorBold, shown, colorReset, dim(RiskLevel(shown))),
+				"  "+renderBar((shown*barWidth)/100, barWidth, fillColor))
+		case 3:
+			for _, c := range r.Citations {
+				for j, l := range wrapText(c.Source, textWidth-4) {
+					prefix := "    "
+					if j == 0 {
+						prefix = dimAccent(fmt.Sprintf("[%d]", c.Index)) + " "
+					}
+					lines = append(lines, "  "+prefix+l)
+				}
+			}
+		case 4:
+			for _, l := range wrapText(r.Conclusion, textWidth) {
+				lines = append(lines, "  "+l)
+			}
+		}
+		lines = append(lines, "")
+	}
+
+	if !s.Animating() {
+		for _, l := range wrapText("--> "+r.ClosingLine, width-2) {
+			lines = append(lines, "  "+italic(bold(l)))
+		}
+	}
+	return lines, focusLine
+}
+
+func (s *Screen) renderFooter(width int) string {
+	text := s.Prompt
+	if text == "" {
+		text = dim("↑/↓ select  enter fold  r regenerate  c copy markdown  t thinker  q quit")
+		if s.Status != "" {
+			text = section(s.Status) + "  " + text
+		}
+	}
+	return colorReset + text
+}
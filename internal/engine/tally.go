@@ -0,0 +1,61 @@
+package engine
+
+import "sort"
+
+// Tally accumulates statistics over a series of analyses, such as an
+// interactive session. The zero value is ready to use.
+type Tally struct {
+	// Count is the number of analyses added.
+	Count int
+	// Citations is the total number of citations fabricated.
+	Citations int
+
+	riskTotal int
+	labels    map[string]int
+}
+
+// Add folds a result into the tally.
+func (t *Tally) Add(r *AnalysisResult) {
+	if t.labels == nil {
+		t.labels = make(map[string]int)
+	}
+	t.Count++
+	t.riskTotal += r.RiskIndex
+	t.Citations += len(r.Citations)
+	for _, p := range r.Probabilities {
+		t.labels[p.Label]++
+	}
+}
+
+// AverageRisk returns the mean risk index, or 0 if nothing was added.
+func (t *Tally) AverageRisk() float64 {
+	if t.Count == 0 {
+		return 0
+	}
+	return float64(t.riskTotal) / float64(t.Count)
+}
+
+// LabelCount is an outcome label and how many analyses it appeared in.
+type LabelCount struct {
+	Label string
+	Count int
+}
+
+// TopLabels returns up to n outcome labels, most frequent first. Ties are
+// broken alphabetically so the order is stable.
+func (t *Tally) TopLabels(n int) []LabelCount {
+	counts := make([]LabelCount, 0, len(t.labels))
+	for label, c := range t.labels {
+		counts = append(counts, LabelCount{Label: label, Count: c})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Label < counts[j].Label
+	})
+	if len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
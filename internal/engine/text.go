@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// visibleLen returns the number of terminal cells s occupies, ignoring ANSI
+// escape sequences. Every rune is assumed to be one cell wide.
+func visibleLen(s string) int {
+	n := 0
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case inEscape:
+			if r == 'm' {
+				inEscape = false
+			}
+		case r == '\033':
+			inEscape = true
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// padRight pads s with spaces until it occupies width visible cells.
+func padRight(s string, width int) string {
+	if n := visibleLen(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// truncate shortens plain (uncolored) text to at most width runes, marking
+// the cut with an ellipsis.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// wrapText breaks plain text into lines of at most width runes, splitting on
+// whitespace. Words longer than width are hard-split.
+func wrapText(s string, width int) []string {
+	if width <= 0 {
+		return []string{s}
+	}
+	var lines []string
+	var current []rune
+	for _, word := range strings.Fields(s) {
+		w := []rune(word)
+		for len(w) > width {
+			if len(current) > 0 {
+				lines = append(lines, string(current))
+				current = nil
+			}
+			lines = append(lines, string(w[:width]))
+			w = w[width:]
+		}
+		switch {
+		case len(current) == 0:
+			current = w
+		case len(current)+1+len(w) <= width:
+			current = append(append(current, ' '), w...)
+		default:
+			lines = append(lines, string(current))
+			current = w
+		}
+	}
+	if len(current) > 0 {
+		lines = append(lines, string(current))
+	}
+	return lines
+}
@@ -0,0 +1,397 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Theme styles the terminal output: the colors of headings, sections, bars
+// and risk levels, the glyphs bars and dividers are drawn with, and the
+// scores at which the risk index changes level.
+//
+// Colors are written as an ANSI color name ("cyan", "bright-red"), a 256-color
+// palette index ("208") or a 24-bit hex value ("#268bd2"); an empty color
+// leaves the text in the terminal's own color. Colors the terminal cannot
+// display are replaced by the closest one it can.
+type Theme struct {
+	Name string `json:"name"`
+
+	// Heading colors titles and thinker names; Section colors section names;
+	// Accent colors probability bars and citation numbers.
+	Heading string `json:"heading"`
+	Section string `json:"section"`
+	Accent  string `json:"accent"`
+	// Calm, Concerning and Alarming color the three risk levels.
+	Calm       string `json:"calm"`
+	Concerning string `json:"concerning"`
+	Alarming   string `json:"alarming"`
+	// Slices color the slices of a pie chart, in turn.
+	Slices []string `json:"slices"`
+
+	// BarFilled and BarEmpty draw the two parts of a bar; Divider draws
+	// section dividers. Each is a single character.
+	BarFilled string `json:"bar_filled"`
+	BarEmpty  string `json:"bar_empty"`
+	Divider   string `json:"divider"`
+
+	// ConcerningAt and AlarmingAt are the risk scores at which the risk
+	// index becomes concerning and alarming.
+	ConcerningAt int `json:"concerning_at"`
+	AlarmingAt   int `json:"alarming_at"`
+}
+
+// ColorDepth is the range of colors a terminal can display.
+type ColorDepth int
+
+// Supported color depths.
+const (
+	// Color16 is the basic ANSI palette of 16 colors.
+	Color16 ColorDepth = iota
+	// Color256 is the xterm palette of 256 colors.
+	Color256
+	// ColorTrue is 24-bit color.
+	ColorTrue
+)
+
+// DefaultTheme is the theme used unless another is chosen.
+var DefaultTheme = Theme{
+	Name:         "default",
+	Heading:      "bright-cyan",
+	Section:      "bright-yellow",
+	Accent:       "bright-cyan",
+	Calm:         "bright-green",
+	Concerning:   "bright-yellow",
+	Alarming:     "bright-red",
+	Slices:       []string{"bright-cyan", "bright-yellow", "bright-magenta", "bright-green", "bright-blue", "bright-red"},
+	BarFilled:    "█",
+	BarEmpty:     "░",
+	Divider:      "─",
+	ConcerningAt: 40,
+	AlarmingAt:   70,
+}
+
+// Themes lists the built-in themes, in the order shown in help text.
+var Themes = []Theme{
+	DefaultTheme,
+	{
+		Name:         "monochrome",
+		BarFilled:    "█",
+		BarEmpty:     "░",
+		Divider:      "─",
+		ConcerningAt: 40,
+		AlarmingAt:   70,
+	},
+	{
+		Name:         "solarized",
+		Heading:      "#268bd2",
+		Section:      "#b58900",
+		Accent:       "#2aa198",
+		Calm:         "#859900",
+		Concerning:   "#cb4b16",
+		Alarming:     "#dc322f",
+		Slices:       []string{"#268bd2", "#2aa198", "#859900", "#b58900", "#cb4b16", "#d33682", "#6c71c4"},
+		BarFilled:    "█",
+		BarEmpty:     "░",
+		Divider:      "─",
+		ConcerningAt: 40,
+		AlarmingAt:   70,
+	},
+	{
+		Name:         "high-contrast",
+		Heading:      "bright-white",
+		Section:      "bright-yellow",
+		Accent:       "bright-white",
+		Calm:         "bright-green",
+		Concerning:   "bright-yellow",
+		Alarming:     "bright-red",
+		Slices:       []string{"bright-white", "bright-yellow", "bright-cyan", "bright-magenta", "bright-green", "bright-red"},
+		BarFilled:    "█",
+		BarEmpty:     "▒",
+		Divider:      "━",
+		ConcerningAt: 40,
+		AlarmingAt:   70,
+	},
+	{
+		// The Okabe-Ito palette, which stays distinguishable under the
+		// common forms of color blindness.
+		Name:         "colorblind-safe",
+		Heading:      "#56b4e9",
+		Section:      "#e69f00",
+		Accent:       "#56b4e9",
+		Calm:         "#0072b2",
+		Concerning:   "#e69f00",
+		Alarming:     "#d55e00",
+		Slices:       []string{"#56b4e9", "#e69f00", "#009e73", "#f0e442", "#0072b2", "#d55e00", "#cc79a7"},
+		BarFilled:    "█",
+		BarEmpty:     "░",
+		Divider:      "─",
+		ConcerningAt: 40,
+		AlarmingAt:   70,
+	},
+	{
+		Name:         "retro-green-phosphor",
+		Heading:      "#66ff66",
+		Section:      "#33ff33",
+		Accent:       "#33cc33",
+		Calm:         "#1f8f1f",
+		Concerning:   "#33cc33",
+		Alarming:     "#99ff99",
+		Slices:       []string{"#99ff99", "#33cc33", "#1f8f1f", "#66ff66", "#2a7a2a", "#ccffcc"},
+		BarFilled:    "▓",
+		BarEmpty:     "░",
+		Divider:      "═",
+		ConcerningAt: 40,
+		AlarmingAt:   70,
+	},
+}
+
+// ThemeNames lists the names of the built-in themes.
+func ThemeNames() []string {
+	names := make([]string, len(Themes))
+	for i, t := range Themes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// ThemeNamed returns the built-in theme called name.
+func ThemeNamed(name string) (Theme, bool) {
+	for _, t := range Themes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// LoadTheme reads a theme from a JSON file. The file may name a built-in
+// theme as its "base", which supplies every field it leaves out; the default
+// theme is the base otherwise. A theme without a name is named after the file.
+func LoadTheme(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	var header struct {
+		Base string `json:"base"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	theme := DefaultTheme
+	if header.Base != "" {
+		base, ok := ThemeNamed(header.Base)
+		if !ok {
+			return Theme{}, fmt.Errorf("%s: unknown base theme %q", path, header.Base)
+		}
+		theme = base
+	}
+	theme.Name = ""
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if theme.Name == "" {
+		theme.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if _, err := theme.compile(ColorTrue); err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return theme, nil
+}
+
+// palette is a theme compiled for a terminal: its colors turned into escape
+// sequences for the terminal's color depth.
+type palette struct {
+	heading, section, accent   string
+	calm, concerning, alarming string
+	slices                     []string
+
+	filled, empty, divider string
+
+	concerningAt, alarmingAt int
+}
+
+// active is the palette all output is drawn with.
+var active = mustCompile(DefaultTheme, Color16)
+
+// SetTheme makes theme the style of all further output, with its colors
+// adapted to a terminal of the given depth.
+func SetTheme(theme Theme, depth ColorDepth) error {
+	p, err := theme.compile(depth)
+	if err != nil {
+		return err
+	}
+	active = p
+	return nil
+}
+
+func mustCompile(theme Theme, depth ColorDepth) palette {
+	p, err := theme.compile(depth)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// compile checks the theme and converts it into a palette.
+func (t Theme) compile(depth ColorDepth) (palette, error) {
+	var p palette
+	var errs []error
+	color := func(field, spec string) string {
+		esc, err := colorEscape(spec, depth)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", field, err))
+		}
+		return esc
+	}
+	glyph := func(field, s string) string {
+		if utf8.RuneCountInString(s) != 1 {
+			errs = append(errs, fmt.Errorf("%s: %q is not a single character", field, s))
+		}
+		return s
+	}
+
+	p.heading = color("heading", t.Heading)
+	p.section = color("section", t.Section)
+	p.accent = color("accent", t.Accent)
+	p.calm = color("calm", t.Calm)
+	p.concerning = color("concerning", t.Concerning)
+	p.alarming = color("alarming", t.Alarming)
+	for i, s := range t.Slices {
+		p.slices = append(p.slices, color(fmt.Sprintf("slices[%d]", i), s))
+	}
+	if len(p.slices) == 0 {
+		p.slices = []string{p.accent}
+	}
+
+	p.filled = glyph("bar_filled", t.BarFilled)
+	p.empty = glyph("bar_empty", t.BarEmpty)
+	p.divider = glyph("divider", t.Divider)
+
+	p.concerningAt, p.alarmingAt = t.ConcerningAt, t.AlarmingAt
+	if p.concerningAt < 1 || p.concerningAt >= p.alarmingAt || p.alarmingAt > 100 {
+		errs = append(errs, fmt.Errorf("thresholds: want 0 < concerning_at < alarming_at <= 100, got %d and %d",
+			p.concerningAt, p.alarmingAt))
+	}
+	return p, errors.Join(errs...)
+}
+
+// ansiColors maps color names to their foreground SGR codes, in the order of
+// the 16-color palette.
+var ansiColors = []struct {
+	name string
+	code int
+	rgb  [3]int
+}{
+	{"black", 30, [3]int{0, 0, 0}},
+	{"red", 31, [3]int{205, 0, 0}},
+	{"green", 32, [3]int{0, 205, 0}},
+	{"yellow", 33, [3]int{205, 205, 0}},
+	{"blue", 34, [3]int{0, 0, 238}},
+	{"magenta", 35, [3]int{205, 0, 205}},
+	{"cyan", 36, [3]int{0, 205, 205}},
+	{"white", 37, [3]int{229, 229, 229}},
+	{"bright-black", 90, [3]int{127, 127, 127}},
+	{"bright-red", 91, [3]int{255, 0, 0}},
+	{"bright-green", 92, [3]int{0, 255, 0}},
+	{"bright-yellow", 93, [3]int{255, 255, 0}},
+	{"bright-blue", 94, [3]int{92, 92, 255}},
+	{"bright-magenta", 95, [3]int{255, 0, 255}},
+	{"bright-cyan", 96, [3]int{0, 255, 255}},
+	{"bright-white", 97, [3]int{255, 255, 255}},
+}
+
+// colorEscape returns the escape sequence that sets the foreground to the
+// color spec, as closely as a terminal of the given depth allows.
+func colorEscape(spec string, depth ColorDepth) (string, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "" {
+		return "", nil
+	}
+	for _, c := range ansiColors {
+		if c.name == spec {
+			return fmt.Sprintf("\033[%dm", c.code), nil
+		}
+	}
+
+	if hex, ok := strings.CutPrefix(spec, "#"); ok {
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return "", fmt.Errorf("invalid hex color %q", spec)
+		}
+		rgb := [3]int{int(v >> 16), int(v >> 8 & 0xff), int(v & 0xff)}
+		switch depth {
+		case ColorTrue:
+			return fmt.Sprintf("\033[38;2;%d;%d;%dm", rgb[0], rgb[1], rgb[2]), nil
+		case Color256:
+			return fmt.Sprintf("\033[38;5;%dm", nearest256(rgb)), nil
+		default:
+			return fmt.Sprintf("\033[%dm", nearest16(rgb)), nil
+		}
+	}
+
+	n, err := strconv.Atoi(spec)
+	if err != nil || n < 0 || n > 255 {
+		return "", fmt.Errorf("unknown color %q", spec)
+	}
+	switch {
+	case n < len(ansiColors):
+		return fmt.Sprintf("\033[%dm", ansiColors[n].code), nil
+	case depth == Color16:
+		return fmt.Sprintf("\033[%dm", nearest16(rgb256(n))), nil
+	default:
+		return fmt.Sprintf("\033[38;5;%dm", n), nil
+	}
+}
+
+// cubeLevels are the channel intensities of the xterm 6x6x6 color cube.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// rgb256 returns the RGB value of a color of the xterm 256-color palette
+// beyond the first 16.
+func rgb256(n int) [3]int {
+	if n >= 232 {
+		v := 8 + 10*(n-232)
+		return [3]int{v, v, v}
+	}
+	n -= 16
+	return [3]int{cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]}
+}
+
+// nearest256 returns the index of the color cube or grayscale ramp entry
+// closest to rgb.
+func nearest256(rgb [3]int) int {
+	best, bestDist := 16, -1
+	for n := 16; n < 256; n++ {
+		if d := colorDistance(rgb, rgb256(n)); bestDist < 0 || d < bestDist {
+			best, bestDist = n, d
+		}
+	}
+	return best
+}
+
+// nearest16 returns the SGR code of the basic ANSI color closest to rgb.
+func nearest16(rgb [3]int) int {
+	best, bestDist := 0, -1
+	for _, c := range ansiColors {
+		if d := colorDistance(rgb, c.rgb); bestDist < 0 || d < bestDist {
+			best, bestDist = c.code, d
+		}
+	}
+	return best
+}
+
+func colorDistance(a, b [3]int) int {
+	d := 0
+	for i := range a {
+		d += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return d
+}
@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestColorEscape(t *testing.T) {
+	tests := []struct {
+		spec  string
+		depth ColorDepth
+		want  string
+	}{
+		{"", ColorTrue, ""},
+		{"cyan", Color16, "\033[36m"},
+		{" Bright-Red ", Color16, "\033[91m"},
+		{"#ff0000", ColorTrue, "\033[38;2;255;0;0m"},
+		{"#ff0000", Color256, "\033[38;5;196m"},
+		{"#ff0000", Color16, "\033[91m"},
+		{"#268BD2", ColorTrue, "\033[38;2;38;139;210m"},
+		{"3", Color256, "\033[33m"},
+		{"208", Color256, "\033[38;5;208m"},
+		{"208", ColorTrue, "\033[38;5;208m"},
+		{"208", Color16, "\033[33m"},
+	}
+	for _, tt := range tests {
+		got, err := colorEscape(tt.spec, tt.depth)
+		if err != nil {
+			t.Errorf("colorEscape(%q, %d): %v", tt.spec, tt.depth, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("colorEscape(%q, %d) = %q, want %q", tt.spec, tt.depth, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"chartreuse", "#12345", "#gggggg", "#1234567", "256", "-1"} {
+		if _, err := colorEscape(spec, ColorTrue); err == nil {
+			t.Errorf("colorEscape(%q) accepted an invalid color", spec)
+		}
+	}
+}
+
+func TestNearest256(t *testing.T) {
+	tests := []struct {
+		rgb  [3]int
+		want int
+	}{
+		{[3]int{0, 0, 0}, 16},
+		{[3]int{255, 255, 255}, 231},
+		{[3]int{255, 0, 0}, 196},
+		{[3]int{95, 135, 175}, 67},
+		{[3]int{128, 128, 128}, 244},
+		{[3]int{100, 140, 170}, 67},
+	}
+	for _, tt := range tests {
+		if got := nearest256(tt.rgb); got != tt.want {
+			t.Errorf("nearest256(%v) = %d, want %d", tt.rgb, got, tt.want)
+		}
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	tests := []struct {
+		concerning, alarming int
+		ok                   bool
+	}{
+		{40, 70, true},
+		{1, 100, true},
+		{0, 70, false},
+		{70, 70, false},
+		{70, 40, false},
+		{40, 101, false},
+	}
+	for _, tt := range tests {
+		theme := DefaultTheme
+		theme.ConcerningAt, theme.AlarmingAt = tt.concerning, tt.alarming
+		if _, err := theme.compile(ColorTrue); (err == nil) != tt.ok {
+			t.Errorf("thresholds %d, %d: err = %v, want ok = %v", tt.concerning, tt.alarming, err, tt.ok)
+		}
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("based on a built-in theme", func(t *testing.T) {
+		theme, err := LoadTheme(write("dusk.json", `{"base": "solarized", "heading": "magenta", "alarming_at": 90}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		solarized, _ := ThemeNamed("solarized")
+		if theme.Name != "dusk" || theme.Heading != "magenta" || theme.AlarmingAt != 90 {
+			t.Errorf("overrides not applied: %+v", theme)
+		}
+		if theme.Section != solarized.Section || theme.ConcerningAt != solarized.ConcerningAt {
+			t.Errorf("base not applied: %+v", theme)
+		}
+	})
+
+	t.Run("named, on the default theme", func(t *testing.T) {
+		theme, err := LoadTheme(write("x.json", `{"name": "mine", "divider": "="}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if theme.Name != "mine" || theme.Divider != "=" || theme.Heading != DefaultTheme.Heading {
+			t.Errorf("theme = %+v", theme)
+		}
+	})
+
+	errors := []struct {
+		name, content, want string
+	}{
+		{"unknown base", `{"base": "vaporwave"}`, "unknown base theme"},
+		{"bad color", `{"calm": "#12"}`, "calm"},
+		{"bad glyph", `{"bar_filled": "##"}`, "bar_filled"},
+		{"thresholds out of order", `{"concerning_at": 80, "alarming_at": 60}`, "thresholds"},
+		{"not JSON", `{`, "bad.json"},
+	}
+	for _, tt := range errors {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadTheme(write("bad.json", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
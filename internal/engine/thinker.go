@@ -1,8 +1,24 @@
 package engine
 
+import "context"
+
 // Thinker is the common interface for all analysis backends.
 // Implementations include the local deterministic engine (internal/local)
 // and the Ollama LLM client (internal/ollama).
 type Thinker interface {
 	Analyze(question string) (*AnalysisResult, error)
 }
+
+// ContextThinker is a Thinker whose analysis can be cancelled. The remote
+// backends implement it, so that an attempt that runs out of time stops its
+// request rather than carrying on in the background.
+type ContextThinker interface {
+	Thinker
+	AnalyzeContext(ctx context.Context, question string) (*AnalysisResult, error)
+}
+
+// Cancellable reports whether t stops working when its context is cancelled.
+func Cancellable(t Thinker) bool {
+	_, ok := t.(ContextThinker)
+	return ok
+}
@@ -0,0 +1,80 @@
+// Package ensemble provides a composite Thinker that consults several
+// backends at once and merges their findings into a single consensus report.
+//
+// Members run concurrently, each under its own timeout. Members that fail are
+// recorded in the result's Consensus rather than aborting the analysis, as
+// long as at least MinMembers of them succeed.
+package ensemble
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// DefaultTimeout is the time budget granted to each member.
+const DefaultTimeout = 2 * time.Minute
+
+// ErrNoConsensus is returned when too few members produce a result.
+var ErrNoConsensus = errors.New("ensemble could not reach a quorum")
+
+// Ensemble is an engine.Thinker that merges the results of its members.
+type Ensemble struct {
+	// Members are the thinkers consulted for every question.
+	Members []engine.NamedThinker
+	// Timeout bounds each member independently.
+	Timeout time.Duration
+	// MinMembers is the number of successful members required to produce a
+	// result. Values below 1 are treated as 1.
+	MinMembers int
+}
+
+// New constructs an Ensemble over the given members.
+func New(members []engine.NamedThinker) *Ensemble {
+	return &Ensemble{
+		Members:    members,
+		Timeout:    DefaultTimeout,
+		MinMembers: 1,
+	}
+}
+
+// Analyze implements engine.Thinker. It returns ErrNoConsensus, listing each
+// member's failure, when fewer than MinMembers members succeed.
+func (e *Ensemble) Analyze(question string) (*engine.AnalysisResult, error) {
+	return e.AnalyzeContext(context.Background(), question)
+}
+
+// AnalyzeContext implements engine.ContextThinker: cancelling ctx cancels
+// every member that supports it.
+func (e *Ensemble) AnalyzeContext(ctx context.Context, question string) (*engine.AnalysisResult, error) {
+	runs := engine.AnalyzeAll(ctx, e.Members, question, e.Timeout)
+
+	var succeeded []engine.Run
+	var failures []string
+	for _, r := range runs {
+		if r.Err != nil {
+			slog.Info("ensemble member failed", "member", r.Name, "elapsed", r.Latency, "err", r.Err)
+			failures = append(failures, fmt.Sprintf("%s: %v", r.Name, r.Err))
+			continue
+		}
+		succeeded = append(succeeded, r)
+	}
+
+	need := e.MinMembers
+	if need < 1 {
+		need = 1
+	}
+	if len(succeeded) < need {
+		return nil, fmt.Errorf("%w: %d of %d members succeeded (need %d): %s",
+			ErrNoConsensus, len(succeeded), len(runs), need, strings.Join(failures, "; "))
+	}
+
+	result := merge(succeeded)
+	result.Consensus.Failures = failures
+	return result, nil
+}
@@ -0,0 +1,265 @@
+package ensemble
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// maxOutcomes caps the merged probability breakdown. Clusters beyond it are
+// folded into a single catch-all entry.
+const maxOutcomes = 5
+
+// leftoverLabel names the catch-all entry for outcomes beyond maxOutcomes.
+const leftoverLabel = "chance of an outcome the panel could not agree on"
+
+// similarityThreshold is the minimum Jaccard similarity between two labels'
+// keyword sets for them to be treated as the same outcome.
+const similarityThreshold = 0.5
+
+// labelFillers are words ignored when comparing outcome labels.
+var labelFillers = map[string]bool{
+	"chance": true, "of": true, "the": true, "a": true, "an": true,
+	"and": true, "or": true, "to": true, "in": true, "on": true,
+	"this": true, "that": true, "it": true, "your": true, "you": true,
+	"about": true, "with": true, "by": true, "for": true,
+}
+
+// merge combines successful member runs into a single result. The risk index
+// is averaged, probabilities are clustered by label similarity, citations are
+// deduplicated, prose comes from the member closest to the consensus risk,
+// and the closing line comes from the most dramatic member.
+func merge(runs []engine.Run) *engine.AnalysisResult {
+	members := make([]string, len(runs))
+	scores := make([]int, len(runs))
+	for i, r := range runs {
+		members[i] = r.Name
+		scores[i] = r.Result.RiskIndex
+	}
+	mean, stddev := riskStats(scores)
+
+	rep := representative(runs, mean)
+	dramatic := mostDramatic(runs)
+
+	return &engine.AnalysisResult{
+		Title:         rep.Title,
+		Summary:       rep.Summary,
+		Probabilities: mergeProbabilities(runs),
+		RiskIndex:     int(math.Round(mean)),
+		Citations:     dedupeCitations(runs),
+		Conclusion:    rep.Conclusion,
+		ClosingLine:   dramatic.ClosingLine,
+		Consensus: &engine.Consensus{
+			Members:      members,
+			RiskScores:   scores,
+			Disagreement: stddev,
+		},
+	}
+}
+
+// riskStats returns the mean and population standard deviation of scores.
+func riskStats(scores []int) (mean, stddev float64) {
+	for _, s := range scores {
+		mean += float64(s)
+	}
+	mean /= float64(len(scores))
+	for _, s := range scores {
+		d := float64(s) - mean
+		stddev += d * d
+	}
+	return mean, math.Sqrt(stddev / float64(len(scores)))
+}
+
+// representative returns the result whose risk index is closest to mean.
+// Ties go to the earliest member.
+func representative(runs []engine.Run, mean float64) *engine.AnalysisResult {
+	best := runs[0].Result
+	for _, r := range runs[1:] {
+		if math.Abs(float64(r.Result.RiskIndex)-mean) < math.Abs(float64(best.RiskIndex)-mean) {
+			best = r.Result
+		}
+	}
+	return best
+}
+
+// mostDramatic returns the result with the highest risk index.
+func mostDramatic(runs []engine.Run) *engine.AnalysisResult {
+	best := runs[0].Result
+	for _, r := range runs[1:] {
+		if r.Result.RiskIndex > best.RiskIndex {
+			best = r.Result
+		}
+	}
+	return best
+}
+
+// --- Probability clustering --------------------------------------------------
+
+type cluster struct {
+	label    string
+	keywords map[string]bool
+	best     float64
+	total    float64
+}
+
+// mergeProbabilities clusters similar outcome labels across members and
+// averages their percentages. Members' breakdowns need not sum to 100 (model
+// output is not normalized), so the merged one is scaled to 100 before it is
+// rounded.
+func mergeProbabilities(runs []engine.Run) []engine.Probability {
+	var clusters []*cluster
+	for _, r := range runs {
+		for _, p := range r.Result.Probabilities {
+			kw := labelKeywords(p.Label)
+			c := closestCluster(clusters, kw)
+			if c == nil {
+				c = &cluster{label: p.Label, keywords: kw}
+				clusters = append(clusters, c)
+			}
+			c.total += p.Percentage
+			if p.Percentage > c.best {
+				c.best = p.Percentage
+				c.label = p.Label
+			}
+		}
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].total > clusters[j].total })
+
+	n := float64(len(runs))
+	var probs []engine.Probability
+	leftover := 0.0
+	for i, c := range clusters {
+		if i >= maxOutcomes-1 && len(clusters) > maxOutcomes {
+			leftover += c.total / n
+			continue
+		}
+		probs = append(probs, engine.Probability{Label: c.label, Percentage: c.total / n})
+	}
+	if leftover > 0 {
+		probs = append(probs, engine.Probability{Label: leftoverLabel, Percentage: leftover})
+	}
+	return roundToHundred(normalize(probs))
+}
+
+// normalize scales probs so their percentages sum to 100. A breakdown that
+// sums to zero is left as it is.
+func normalize(probs []engine.Probability) []engine.Probability {
+	sum := 0.0
+	for _, p := range probs {
+		sum += p.Percentage
+	}
+	if sum <= 0 {
+		return probs
+	}
+	for i := range probs {
+		probs[i].Percentage *= 100 / sum
+	}
+	return probs
+}
+
+// closestCluster returns the cluster most similar to keywords, or nil if none
+// reaches similarityThreshold.
+func closestCluster(clusters []*cluster, keywords map[string]bool) *cluster {
+	var best *cluster
+	bestScore := similarityThreshold
+	for _, c := range clusters {
+		if s := jaccard(c.keywords, keywords); s >= bestScore {
+			best, bestScore = c, s
+		}
+	}
+	return best
+}
+
+// labelKeywords extracts the meaningful lowercase words of an outcome label.
+func labelKeywords(label string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kw := make(map[string]bool, len(words))
+	for _, w := range words {
+		if !labelFillers[w] {
+			kw[w] = true
+		}
+	}
+	return kw
+}
+
+// jaccard returns the Jaccard similarity of two keyword sets.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// roundToHundred rounds each percentage of a breakdown summing to 100 to one
+// decimal place, keeping the sum at exactly 100: every entry is rounded down
+// to a tenth, and the tenths still missing go to the entries that lost the
+// most. No entry becomes negative.
+func roundToHundred(probs []engine.Probability) []engine.Probability {
+	if len(probs) == 0 {
+		return probs
+	}
+	remainders := make([]float64, len(probs))
+	tenths := 0
+	for i, p := range probs {
+		t := math.Floor(math.Max(p.Percentage, 0)*10 + 1e-9)
+		remainders[i] = p.Percentage*10 - t
+		probs[i].Percentage = t
+		tenths += int(t)
+	}
+
+	order := make([]int, len(probs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for i := 0; tenths < 1000 && i < len(order); i++ {
+		probs[order[i]].Percentage++
+		tenths++
+	}
+	for i := range probs {
+		probs[i].Percentage /= 10
+	}
+	return probs
+}
+
+// --- Citation deduplication --------------------------------------------------
+
+// dedupeCitations collects every member's citations, dropping sources that
+// differ only in case, punctuation or spacing, and renumbers them from 1.
+func dedupeCitations(runs []engine.Run) []engine.Citation {
+	seen := make(map[string]bool)
+	var citations []engine.Citation
+	for _, r := range runs {
+		for _, c := range r.Result.Citations {
+			key := citationKey(c.Source)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			citations = append(citations, engine.Citation{Index: len(citations) + 1, Source: c.Source})
+		}
+	}
+	return citations
+}
+
+func citationKey(source string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(source) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
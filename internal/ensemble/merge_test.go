@@ -0,0 +1,54 @@
+package ensemble
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+func TestMergeProbabilitiesNormalizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		members [][]engine.Probability
+	}{
+		{"sums to 100", [][]engine.Probability{
+			{{Label: "regret", Percentage: 60}, {Label: "relief", Percentage: 40}},
+			{{Label: "regret", Percentage: 30}, {Label: "panic", Percentage: 70}},
+		}},
+		{"sums past 100", [][]engine.Probability{
+			{{Label: "regret", Percentage: 80}, {Label: "relief", Percentage: 40}},
+			{{Label: "panic", Percentage: 90}, {Label: "shame", Percentage: 30}},
+		}},
+		{"sums short of 100", [][]engine.Probability{
+			{{Label: "regret", Percentage: 20}, {Label: "relief", Percentage: 10}},
+		}},
+		{"thirds", [][]engine.Probability{
+			{{Label: "a", Percentage: 33.33}, {Label: "b", Percentage: 33.33}, {Label: "c", Percentage: 33.33}},
+		}},
+		{"tiny leftover", [][]engine.Probability{
+			{{Label: "one", Percentage: 40.06}, {Label: "two", Percentage: 30.06}, {Label: "three", Percentage: 20.06},
+				{Label: "four", Percentage: 9.06}, {Label: "five", Percentage: 0.7}, {Label: "six", Percentage: 0.03},
+				{Label: "seven", Percentage: 0.03}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := make([]engine.Run, len(tt.members))
+			for i, probs := range tt.members {
+				runs[i] = engine.Run{Result: &engine.AnalysisResult{Probabilities: probs}}
+			}
+			merged := mergeProbabilities(runs)
+			sum := 0.0
+			for _, p := range merged {
+				if p.Percentage < 0 {
+					t.Errorf("%q has negative percentage %v", p.Label, p.Percentage)
+				}
+				sum += p.Percentage
+			}
+			if math.Abs(sum-100) > 1e-9 {
+				t.Errorf("merged percentages sum to %v, want 100: %+v", sum, merged)
+			}
+		})
+	}
+}
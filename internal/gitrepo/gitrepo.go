@@ -0,0 +1,199 @@
+// Package gitrepo inspects a local git repository by shelling out to plain
+// git. It gathers just enough about a change -- which files it touches, how
+// many lines move, and the message that goes with it -- for the commit
+// generator in internal/local to overanalyze it.
+package gitrepo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// File is one file touched by a change.
+type File struct {
+	Path    string
+	Added   int
+	Deleted int
+	// Binary is set for files git reports no line counts for.
+	Binary bool
+}
+
+// Change describes the change under analysis.
+type Change struct {
+	// Files lists every file touched, in the order git reports them.
+	Files []File
+	// Message is the commit message the change is judged by.
+	Message string
+	// Staged is set when Files come from the index; otherwise they are the
+	// files of the last commit.
+	Staged bool
+}
+
+// Lines reports the total number of lines added and deleted.
+func (c *Change) Lines() int {
+	n := 0
+	for _, f := range c.Files {
+		n += f.Added + f.Deleted
+	}
+	return n
+}
+
+// Subject returns the first line of the message.
+func (c *Change) Subject() string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return strings.TrimSpace(subject)
+}
+
+// ErrGitNotFound is returned when the git executable is not on PATH.
+var ErrGitNotFound = errors.New("git is not installed or not on PATH")
+
+// ErrNotRepository is returned when dir is not inside a git work tree.
+var ErrNotRepository = errors.New("not inside a git repository")
+
+// ErrNoChange is returned when nothing is staged and there is no commit to
+// fall back to.
+var ErrNoChange = errors.New("nothing staged and no commits yet")
+
+// Read describes the change in the repository containing dir: the staged
+// diff together with the last commit message. When nothing is staged, the
+// last commit itself is described instead.
+func Read(dir string) (*Change, error) {
+	if _, err := git(dir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		if errors.Is(err, ErrGitNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotRepository, dir)
+	}
+
+	// An unborn branch has no HEAD; its message is simply empty.
+	message, _ := git(dir, "log", "-1", "--format=%B")
+
+	out, err := git(dir, "diff", "--cached", "--numstat", "-M")
+	if err != nil {
+		return nil, err
+	}
+	if files := parseNumstat(out); len(files) > 0 {
+		return &Change{Files: files, Message: strings.TrimSpace(message), Staged: true}, nil
+	}
+
+	out, err = git(dir, "show", "--numstat", "-M", "--format=", "HEAD")
+	if err != nil {
+		return nil, ErrNoChange
+	}
+	return &Change{Files: parseNumstat(out), Message: strings.TrimSpace(message)}, nil
+}
+
+// ReadMessageFile reads a commit message file as git hands it to a
+// prepare-commit-msg hook in the repository containing dir. It returns the
+// message without its comment lines, and the prefix those lines start with
+// (see CommentString).
+func ReadMessageFile(dir, path string) (message, comment string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	raw := string(data)
+	comment = CommentString(dir, raw)
+	var lines []string
+	for _, line := range strings.Split(raw, "\n") {
+		if !strings.HasPrefix(line, comment) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), comment, nil
+}
+
+// autoCommentChars are the characters git picks a comment character from
+// when core.commentChar is "auto", in order of preference.
+const autoCommentChars = "#;@!$%^&|:"
+
+// CommentString returns the prefix that starts comment lines in commit
+// messages of the repository containing dir: core.commentString or
+// core.commentChar, and "#" when neither is set. When it is "auto", git
+// chooses a character for each message; it is then read back from raw, the
+// message file as git wrote it, whose trailing comment block uses it.
+func CommentString(dir, raw string) string {
+	comment := ""
+	for _, key := range []string{"core.commentString", "core.commentChar"} {
+		if out, err := git(dir, "config", "--get", key); err == nil {
+			comment = strings.TrimRight(out, "\r\n")
+			break
+		}
+	}
+	switch comment {
+	case "":
+		return "#"
+	case "auto":
+		lines := strings.Split(raw, "\n")
+		for i := len(lines) - 1; i >= 0; i-- {
+			line := lines[i]
+			if line != "" && strings.IndexByte(autoCommentChars, line[0]) >= 0 &&
+				(len(line) == 1 || line[1] == ' ') {
+				return line[:1]
+			}
+		}
+		return "#"
+	}
+	return comment
+}
+
+// HookPath returns the path of the named hook in the repository containing
+// dir, honouring core.hooksPath and linked work trees.
+func HookPath(dir, hook string) (string, error) {
+	out, err := git(dir, "rev-parse", "--git-path", "hooks/"+hook)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrNotRepository, dir)
+	}
+	path := strings.TrimSpace(out)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, nil
+}
+
+// parseNumstat parses the output of git's --numstat option: one
+// "added<TAB>deleted<TAB>path" line per file, with "-" counts for binaries.
+func parseNumstat(out string) []File {
+	var files []File
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		f := File{Path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			f.Binary = true
+		} else {
+			f.Added, _ = strconv.Atoi(fields[0])
+			f.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// git runs git with args in dir and returns its standard output. A failing
+// command's error carries git's own message.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", ErrGitNotFound
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,183 @@
+package gitrepo
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// newRepo initialises an empty repository in a temporary directory.
+func newRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	// Keep the user's own git configuration out of the tests.
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	dir := t.TempDir()
+	run(t, dir, "init", "-q")
+	run(t, dir, "config", "user.name", "Test")
+	run(t, dir, "config", "user.email", "test@example.com")
+	run(t, dir, "config", "commit.gpgsign", "false")
+	return dir
+}
+
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if _, err := git(dir, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadStaged(t *testing.T) {
+	dir := newRepo(t)
+	writeFile(t, dir, "README.md", "hello\n")
+	run(t, dir, "add", ".")
+	run(t, dir, "commit", "-q", "-m", "Initial commit\n\nWith a body.")
+
+	writeFile(t, dir, "README.md", "hello\nworld\nagain\n")
+	writeFile(t, dir, "db/migrations/001.sql", "CREATE TABLE t;\n")
+	writeFile(t, dir, "logo.bin", "\x00\x01\x02")
+	run(t, dir, "add", ".")
+
+	change, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !change.Staged {
+		t.Error("Staged = false, want true")
+	}
+	if got := change.Subject(); got != "Initial commit" {
+		t.Errorf("Subject() = %q", got)
+	}
+	want := []File{
+		{Path: "README.md", Added: 2},
+		{Path: "db/migrations/001.sql", Added: 1},
+		{Path: "logo.bin", Binary: true},
+	}
+	if !slices.Equal(change.Files, want) {
+		t.Errorf("Files = %+v, want %+v", change.Files, want)
+	}
+	if change.Lines() != 3 {
+		t.Errorf("Lines() = %d, want 3", change.Lines())
+	}
+}
+
+func TestReadFallsBackToLastCommit(t *testing.T) {
+	dir := newRepo(t)
+	writeFile(t, dir, "main.go", "package main\n")
+	run(t, dir, "add", ".")
+	run(t, dir, "commit", "-q", "-m", "wip")
+
+	change, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.Staged {
+		t.Error("Staged = true, want false")
+	}
+	if want := []File{{Path: "main.go", Added: 1}}; !slices.Equal(change.Files, want) {
+		t.Errorf("Files = %+v, want %+v", change.Files, want)
+	}
+	if change.Message != "wip" {
+		t.Errorf("Message = %q, want %q", change.Message, "wip")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	dir := newRepo(t)
+	if _, err := Read(dir); !errors.Is(err, ErrNoChange) {
+		t.Errorf("empty repository: err = %v, want ErrNoChange", err)
+	}
+	if _, err := Read(t.TempDir()); !errors.Is(err, ErrNotRepository) {
+		t.Errorf("plain directory: err = %v, want ErrNotRepository", err)
+	}
+}
+
+func TestHookPath(t *testing.T) {
+	dir := newRepo(t)
+	path, err := HookPath(dir, "prepare-commit-msg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, ".git", "hooks", "prepare-commit-msg"); path != want {
+		t.Errorf("HookPath = %q, want %q", path, want)
+	}
+
+	run(t, dir, "config", "core.hooksPath", "githooks")
+	path, err = HookPath(dir, "prepare-commit-msg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "githooks", "prepare-commit-msg"); path != want {
+		t.Errorf("with core.hooksPath: HookPath = %q, want %q", path, want)
+	}
+}
+
+func TestReadMessageFile(t *testing.T) {
+	const message = "Fix the thing\n\n#hashtag stays\n"
+	tests := []struct {
+		name        string
+		commentChar string
+		file        string
+		wantComment string
+		wantMessage string
+	}{
+		{
+			name:        "default",
+			file:        "Fix the thing\n\n# Please enter the commit message.\n#\n",
+			wantComment: "#",
+			wantMessage: "Fix the thing",
+		},
+		{
+			name:        "configured",
+			commentChar: ";",
+			file:        message + "; Please enter the commit message.\n;\n",
+			wantComment: ";",
+			wantMessage: "Fix the thing\n\n#hashtag stays",
+		},
+		{
+			name:        "auto",
+			commentChar: "auto",
+			file:        message + "; Please enter the commit message.\n;\n",
+			wantComment: ";",
+			wantMessage: "Fix the thing\n\n#hashtag stays",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newRepo(t)
+			if tt.commentChar != "" {
+				run(t, dir, "config", "core.commentChar", tt.commentChar)
+			}
+			path := filepath.Join(dir, ".git", "COMMIT_EDITMSG")
+			writeFile(t, dir, ".git/COMMIT_EDITMSG", tt.file)
+
+			message, comment, err := ReadMessageFile(dir, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if comment != tt.wantComment {
+				t.Errorf("comment = %q, want %q", comment, tt.wantComment)
+			}
+			if message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Package history keeps a small local log of past analyses, so that the
+// stats command can chart how the Emotional Risk Index has evolved.
+//
+// The log is a file of JSON lines, one per analysis, oldest first. It holds
+// only what stats charts -- when, which thinker, the risk index and the
+// outcomes -- never the question asked. Only the most recent MaxEntries
+// analyses are kept.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// MaxEntries is the number of analyses the log retains.
+const MaxEntries = 500
+
+// trimSlack is how far the log may grow past MaxEntries before it is trimmed,
+// so that it is rewritten once every trimSlack analyses rather than each time.
+const trimSlack = 100
+
+// EnvPath names the environment variable that overrides the log location.
+// Setting it to "off" disables the log altogether.
+const EnvPath = "OVERTHINK_HISTORY"
+
+// Off is the value of EnvPath that disables the log.
+const Off = "off"
+
+// Entry is one logged analysis.
+type Entry struct {
+	Time          time.Time            `json:"time"`
+	Thinker       string               `json:"thinker,omitempty"`
+	RiskIndex     int                  `json:"risk_index"`
+	Probabilities []engine.Probability `json:"probabilities"`
+}
+
+// Result rebuilds the statistical part of the logged analysis, for use with
+// engine.Tally.
+func (e Entry) Result() *engine.AnalysisResult {
+	return &engine.AnalysisResult{RiskIndex: e.RiskIndex, Probabilities: e.Probabilities}
+}
+
+// DefaultPath returns the log location: $OVERTHINK_HISTORY if set, otherwise
+// overthink/history.ndjson in the user's configuration directory. It returns
+// "" when the log is disabled or no location can be determined.
+func DefaultPath() string {
+	if p := os.Getenv(EnvPath); p != "" {
+		if p == Off {
+			return ""
+		}
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "overthink", "history.ndjson")
+}
+
+// Load reads every entry in the log at path. A missing log is empty. Lines
+// that cannot be parsed are skipped.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// Append adds an entry for result to the log at path. The entry is written
+// as a single line to the end of the file. Once the log holds trimSlack
+// entries more than MaxEntries, it is trimmed back to the most recent
+// MaxEntries. Both happen under the log's lock, so concurrent runs never lose
+// each other's entries, not even to a trim.
+func Append(path, thinker string, result *engine.AnalysisResult) error {
+	line, err := json.Marshal(Entry{
+		Time:          time.Now().UTC(),
+		Thinker:       thinker,
+		RiskIndex:     result.RiskIndex,
+		Probabilities: result.Probabilities,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return trim(path)
+}
+
+// Locking waits up to lockWait for another run to release the log. A lock
+// older than staleLock belongs to a run that died holding it, and is broken.
+const (
+	lockWait  = 5 * time.Second
+	staleLock = 30 * time.Second
+)
+
+// ErrLocked is returned when another run holds the log's lock for too long.
+var ErrLocked = errors.New("history log is locked by another run")
+
+// lock takes the lock on the log at path: a <path>.lock file that only one
+// run can create. It returns the function that releases it.
+func lock(path string) (unlock func(), err error) {
+	name := path + ".lock"
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(name) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(name); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(name)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %s", ErrLocked, name)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// trim rewrites the log at path with only its most recent MaxEntries lines,
+// once it has grown trimSlack lines past that. The log is rewritten through a
+// temporary file, so an interrupted write never leaves it truncated.
+func trim(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.Count(data, []byte{'\n'}) <= MaxEntries+trimSlack {
+		return nil
+	}
+	lines := bytes.SplitAfter(bytes.TrimRight(data, "\n"), []byte{'\n'})
+	lines = lines[len(lines)-MaxEntries:]
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".history-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	for _, line := range lines {
+		w.Write(line)
+	}
+	w.WriteByte('\n')
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,149 @@
+package history
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+func testResult(risk int) *engine.AnalysisResult {
+	return &engine.AnalysisResult{
+		RiskIndex:     risk,
+		Probabilities: []engine.Probability{{Label: "chance of regret", Percentage: 100}},
+	}
+}
+
+func TestConcurrentAppendsKeepEveryEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overthink", "history.ndjson")
+	const n = 50
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Append(path, "local", testResult(i)); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != n {
+		t.Errorf("Load returned %d entries, want %d", len(entries), n)
+	}
+}
+
+func TestAppendTrimsOccasionally(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+	for i := range MaxEntries + trimSlack {
+		if err := Append(path, "local", testResult(i%101)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, _ := Load(path)
+	if len(entries) != MaxEntries+trimSlack {
+		t.Fatalf("log trimmed early: %d entries", len(entries))
+	}
+
+	if err := Append(path, "local", testResult(42)); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ = Load(path)
+	if len(entries) != MaxEntries {
+		t.Fatalf("after trimming: %d entries, want %d", len(entries), MaxEntries)
+	}
+	if last := entries[len(entries)-1]; last.RiskIndex != 42 {
+		t.Errorf("newest entry lost in trimming: %+v", last)
+	}
+}
+
+func TestConcurrentAppendsSurviveTrimming(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+	for i := range MaxEntries + trimSlack - 10 {
+		if err := Append(path, "local", testResult(i%101)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The log crosses the trim threshold part-way through these appends.
+	const n = 20
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Append(path, "racer", testResult(99)); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	racers := 0
+	for _, e := range entries {
+		if e.Thinker == "racer" {
+			racers++
+		}
+	}
+	if racers != n {
+		t.Errorf("%d of %d concurrent entries survived trimming", racers, n)
+	}
+}
+
+func TestStaleLockIsBroken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+	name := path + ".lock"
+	if err := os.WriteFile(name, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLock)
+	if err := os.Chtimes(name, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Append(path, "local", testResult(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(name); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+}
+
+func TestAppendWaitsForLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+	unlock, err := lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() { done <- Append(path, "local", testResult(1)) }()
+	select {
+	case err := <-done:
+		unlock()
+		t.Fatalf("Append did not wait for the lock (err = %v)", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := Load(path); len(entries) != 1 {
+		t.Errorf("Load returned %d entries, want 1", len(entries))
+	}
+}
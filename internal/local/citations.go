@@ -42,11 +42,18 @@ var authorSuffixes = []string{
 	"(Peer-reviewed by one very tired colleague)",
 }
 
-// generateCitations produces 2-4 fabricated academic citations.
-func generateCitations(rng *rand.Rand) []engine.Citation {
-	count := 2 + rng.Intn(3)
+// generateCitations produces fabricated academic citations, 2-4 of them at
+// the default intensity.
+func generateCitations(p profile, rng *rand.Rand) []engine.Citation {
+	return citationsFrom(rng, journalNames, p.citations)
+}
+
+// citationsFrom produces fabricated citations of journals from pool, as many
+// as the inclusive range counts allows.
+func citationsFrom(rng *rand.Rand, pool []string, counts [2]int) []engine.Citation {
+	count := between(rng, counts)
 
-	shuffled := utils.ShuffleStrings(rng, journalNames)
+	shuffled := utils.ShuffleStrings(rng, pool)
 	selected := shuffled[:count]
 
 	citations := make([]engine.Citation, count)
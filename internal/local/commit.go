@@ -0,0 +1,261 @@
+package local
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/gitrepo"
+	"github.com/rishichawda/overthinker/internal/utils"
+)
+
+// CommitEngine is a local Thinker specialised in code changes. Its risk
+// index is driven by the size of the change, the number of files touched and
+// the paths it dares to modify, rather than by the words of a question.
+type CommitEngine struct {
+	// Change is the change under analysis.
+	Change *gitrepo.Change
+	// Seed, when non-zero or when Seeded is set, makes every analysis
+	// reproducible.
+	Seed int64
+	// Seeded makes Seed apply even when it is zero.
+	Seeded bool
+	// Intensity sets how dramatic the analysis is, as for Engine.
+	Intensity engine.Intensity
+}
+
+// NewCommit constructs a CommitEngine for change.
+func NewCommit(change *gitrepo.Change) *CommitEngine {
+	return &CommitEngine{Change: change}
+}
+
+// Analyze implements engine.Thinker. The question is the commit message;
+// when empty, the change's own message is used. It never returns an error.
+func (e *CommitEngine) Analyze(question string) (*engine.AnalysisResult, error) {
+	rng := utils.NewRand()
+	if e.Seeded || e.Seed != 0 {
+		rng = utils.NewSeededRand(e.Seed)
+	}
+	if question == "" {
+		question = e.Change.Message
+	}
+
+	flagged := flaggedPaths(e.Change.Files)
+	p := profileFor(e.Intensity)
+	return &engine.AnalysisResult{
+		Title:         generateCommitTitle(question, p, rng),
+		Summary:       generateCommitSummary(e.Change, flagged, rng),
+		Probabilities: probabilitiesFrom(rng, commitOutcomeLabels, p.probabilities),
+		RiskIndex:     calculateCommitRisk(e.Change, question, flagged, p, rng),
+		Citations:     citationsFrom(rng, commitJournalNames, p.citations),
+		Conclusion:    utils.PickString(rng, commitConclusions),
+		ClosingLine:   utils.PickString(rng, commitClosingLines),
+	}, nil
+}
+
+// --- Risk --------------------------------------------------------------------
+
+// scaryPath is a path fragment that makes reviewers nervous.
+type scaryPath struct {
+	fragment string
+	score    int
+	reason   string
+}
+
+// scaryPaths are matched, in lower case, against every path in the change.
+// Each reason counts once, however many files share it.
+var scaryPaths = []scaryPath{
+	{"migration", 25, "database migration"},
+	{"schema", 15, "schema change"},
+	{".env", 20, "environment secrets"},
+	{"secret", 20, "secrets"},
+	{"auth", 18, "authentication"},
+	{"security", 15, "security code"},
+	{"crypto", 15, "cryptography"},
+	{"payment", 18, "payments"},
+	{"billing", 18, "payments"},
+	{"production", 15, "production configuration"},
+	{"terraform", 18, "infrastructure"},
+	{".tf", 18, "infrastructure"},
+	{"helm", 12, "infrastructure"},
+	{"k8s", 12, "infrastructure"},
+	{".github/workflows", 15, "CI pipeline"},
+	{".gitlab-ci", 15, "CI pipeline"},
+	{"jenkinsfile", 15, "CI pipeline"},
+	{"dockerfile", 10, "container image"},
+	{"makefile", 8, "build system"},
+	{"go.mod", 8, "dependency manifest"},
+	{"go.sum", 6, "dependency manifest"},
+	{"package.json", 8, "dependency manifest"},
+	{"package-lock.json", 6, "dependency manifest"},
+	{"requirements.txt", 8, "dependency manifest"},
+	{"cargo.toml", 8, "dependency manifest"},
+	{"gemfile", 8, "dependency manifest"},
+	{"vendor/", 10, "vendored code"},
+	{"license", 10, "licensing"},
+}
+
+// commitKeywords are words in a commit message that betray the author's state
+// of mind.
+var commitKeywords = map[string]int{
+	"hotfix": 18, "hack": 15, "wip": 12, "temp": 10, "tmp": 10,
+	"revert": 12, "quick": 8, "urgent": 12, "asap": 12, "final": 10,
+	"rewrite": 15, "refactor": 10, "cleanup": 6, "fix": 5, "fixes": 5,
+	"force": 10, "disable": 10, "remove": 8, "delete": 10, "drop": 15,
+	"friday": 20, "prod": 12, "production": 12, "migration": 10,
+	"todo": 6, "fixme": 10, "oops": 14, "again": 10, "actually": 10,
+}
+
+// pathFlag is a reason for concern found in a change, with the first file
+// that raised it.
+type pathFlag struct {
+	path   string
+	score  int
+	reason string
+}
+
+// flaggedPaths returns the distinct reasons the change's paths are alarming,
+// in the order of scaryPaths.
+func flaggedPaths(files []gitrepo.File) []pathFlag {
+	var flagged []pathFlag
+	seen := make(map[string]bool)
+	for _, sp := range scaryPaths {
+		if seen[sp.reason] {
+			continue
+		}
+		for _, f := range files {
+			if strings.Contains(strings.ToLower(f.Path), sp.fragment) {
+				flagged = append(flagged, pathFlag{path: f.Path, score: sp.score, reason: sp.reason})
+				seen[sp.reason] = true
+				break
+			}
+		}
+	}
+	return flagged
+}
+
+// calculateCommitRisk computes the Emotional Risk Index (0-100) of a change:
+// a small random base shifted by the profile, plus points for lines changed,
+// files touched, flagged paths and the commit message's keywords.
+func calculateCommitRisk(change *gitrepo.Change, message string, flagged []pathFlag, p profile, rng *rand.Rand) int {
+	total := max(0, 10+rng.Intn(15)+p.riskShift)
+	total += min(change.Lines()/25, 30)
+	total += min(2*len(change.Files), 20)
+	for _, f := range flagged {
+		total += f.score
+	}
+
+	seen := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(message)) {
+		clean := strings.Trim(word, ".,?!;:'\"()[]")
+		if score, ok := commitKeywords[clean]; ok && !seen[clean] {
+			total += score
+			seen[clean] = true
+		}
+	}
+	return min(total, 100)
+}
+
+// --- Prose -------------------------------------------------------------------
+
+var commitNouns = []string{
+	"REGRESSION CASCADE",
+	"DEPLOYMENT RECKONING",
+	"ROLLBACK PARADOX",
+	"MERGE CONFLICT OF THE SOUL",
+	"BLAME TRAJECTORY",
+	"CODE REVIEW VORTEX",
+	"TECHNICAL DEBT EVENT",
+	"PRODUCTION INCIDENT PRECURSOR",
+	"DIFF OF NO RETURN",
+	"PIPELINE UNDERTOW",
+}
+
+func generateCommitTitle(message string, p profile, rng *rand.Rand) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	return fmt.Sprintf("%s %s OF %s",
+		utils.PickString(rng, p.prefixes),
+		utils.PickString(rng, commitNouns),
+		titleSubject(subject, "THIS CHANGE"))
+}
+
+var commitSummaryOpeners = []string{
+	"Forensic inspection of the diff is complete. The findings have been forwarded to your future self.",
+	"The change was replayed against 847 hypothetical production environments. Several of them are still on fire.",
+	"A multi-pass static analysis of your intentions reveals a commit that is confident, ambitious and unreviewed.",
+	"The diff has been cross-referenced with every post-mortem ever written. The overlap is statistically uncomfortable.",
+	"Preliminary review suggests this change works on your machine. The system is not your machine.",
+	"The commit was examined line by line, then again with the lights off. It looked worse the second time.",
+}
+
+// generateCommitSummary opens with a dramatic line and then states the
+// actual dimensions of the change, which are alarming enough on their own.
+func generateCommitSummary(change *gitrepo.Change, flagged []pathFlag, rng *rand.Rand) string {
+	var sb strings.Builder
+	sb.WriteString(utils.PickString(rng, commitSummaryOpeners))
+
+	source := "The last commit"
+	if change.Staged {
+		source = "The staged change"
+	}
+	fmt.Fprintf(&sb, " %s touches %s across %s.",
+		source, engine.Plural(len(change.Files), "file"), engine.Plural(change.Lines(), "line"))
+
+	if len(flagged) > 0 {
+		reasons := make([]string, len(flagged))
+		for i, f := range flagged {
+			reasons[i] = fmt.Sprintf("%s (%s)", f.path, f.reason)
+		}
+		fmt.Fprintf(&sb, " Flagged for concern: %s.", strings.Join(reasons, ", "))
+	}
+	return sb.String()
+}
+
+var commitOutcomeLabels = []string{
+	"chance of reverting this on Friday",
+	"chance of a merge conflict with a branch you forgot about",
+	"chance the tests pass for the wrong reason",
+	"chance of a follow-up commit titled \"fix\"",
+	"chance a reviewer asks \"why?\" and you don't know",
+	"chance of breaking something three services away",
+	"chance of being paged about this at 3 a.m.",
+	"chance this becomes someone else's legacy code",
+	"chance of amending this commit twice",
+	"chance the CI is flaky rather than you being wrong",
+	"chance of a git blame in six months that leads to you",
+	"chance of force-pushing to fix the fix",
+	"chance it works flawlessly and nobody notices",
+	"chance of writing a post-mortem about this very line",
+}
+
+var commitJournalNames = []string{
+	"Journal of Irreversible Deployments",
+	"Proceedings of the Friday Afternoon Release Symposium",
+	"Transactions on Merge Conflict Resolution",
+	"Annals of Works-On-My-Machine Studies",
+	"Quarterly Review of Unreviewed Pull Requests",
+	"Institute for Applied Rollback Science",
+	"Bulletin of the Society for Flaky Tests",
+	"Archives of Technical Debt",
+	"Compendium of Post-Mortems (Blameless Edition)",
+	"Review of Force-Push Ethics",
+}
+
+var commitConclusions = []string{
+	"The system recommends a second reviewer, a feature flag and a quiet room. You will push it anyway.",
+	"Every change is a hypothesis about production. This one is a bold hypothesis.",
+	"The diff is complete. The consequences are pending review by reality.",
+	"History suggests this change will be fine, right up until the moment it is not. The system will have been right.",
+	"On balance, the commit is defensible. On Friday, it is not.",
+	"The system has no further objections, only forebodings. They have been logged.",
+}
+
+var commitClosingLines = []string{
+	"git commit --amend exists for a reason. That reason is you.",
+	"The CI pipeline has been notified. It is preparing itself emotionally.",
+	"Somewhere, a future maintainer just felt a chill.",
+	"Ship it. Then watch the dashboards like a hawk with trust issues.",
+	"Consider writing the revert commit now, just to have it ready.",
+	"The system approves this change with the enthusiasm of a reviewer at 5:55 p.m.",
+}
@@ -9,31 +9,92 @@ import (
 	"strings"
 
 	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/persona"
 	"github.com/rishichawda/overthinker/internal/utils"
 )
 
 // Engine is the local deterministic Thinker implementation.
-type Engine struct{}
+type Engine struct {
+	// Seed, when non-zero or when Seeded is set, makes every analysis
+	// reproducible. Otherwise a fresh time-based seed is drawn for each call.
+	Seed int64
+	// Seeded makes Seed apply even when it is zero.
+	Seeded bool
+	// Context is background material for every question, such as a commit
+	// message or a design document. Its risk keywords count towards the
+	// risk index alongside the question's own.
+	Context string
+	// Intensity sets how dramatic the analysis is: the range of the risk
+	// index, the number of probabilities and citations and the tone of the
+	// prose. The zero value is engine.DefaultIntensity.
+	Intensity engine.Intensity
+	// Persona, when set, narrates the analysis: its template pools replace
+	// the engine's own.
+	Persona *persona.Persona
+}
 
 // New constructs a local Engine.
 func New() *Engine { return &Engine{} }
 
+// NewSeeded constructs a local Engine whose output is fully determined by seed.
+func NewSeeded(seed int64) *Engine { return &Engine{Seed: seed, Seeded: true} }
+
 // Analyze implements engine.Thinker. It never returns an error.
 func (e *Engine) Analyze(question string) (*engine.AnalysisResult, error) {
-	rng := utils.NewRand()
+	rng := e.rand()
+	p := profileFor(e.Intensity).with(e.Persona)
 	return &engine.AnalysisResult{
-		Title:         generateTitle(question, rng),
-		Summary:       generateSummary(rng),
-		Probabilities: generateProbabilities(rng),
-		RiskIndex:     calculateRiskIndex(question, rng),
-		Citations:     generateCitations(rng),
-		Conclusion:    generateConclusion(rng),
-		ClosingLine:   generateClosingLine(rng),
+		Title:         generateTitle(question, p, rng),
+		Summary:       generateSummary(p, rng),
+		Probabilities: generateProbabilities(p, rng),
+		RiskIndex:     calculateRiskIndex(e.riskText(question), p, rng),
+		RiskProfile:   RiskProfile(e.riskText(question)),
+		Citations:     generateCitations(p, rng),
+		Conclusion:    generateConclusion(p, rng),
+		ClosingLine:   generateClosingLine(p, rng),
 	}, nil
 }
 
+// Statistics holds the numeric half of an analysis: the keyword-driven risk
+// index and a probability breakdown that sums to exactly 100.
+type Statistics struct {
+	RiskIndex     int
+	Probabilities []engine.Probability
+}
+
+// Statistics computes only the numbers for question, leaving the prose to
+// someone else. It is used by hybrid mode, where an LLM writes the report
+// around locally computed figures.
+func (e *Engine) Statistics(question string) Statistics {
+	rng := e.rand()
+	p := profileFor(e.Intensity)
+	return Statistics{
+		RiskIndex:     calculateRiskIndex(e.riskText(question), p, rng),
+		Probabilities: generateProbabilities(p, rng),
+	}
+}
+
+// riskText is the text mined for risk keywords: the question followed by
+// the engine's context, if any.
+func (e *Engine) riskText(question string) string {
+	if e.Context == "" {
+		return question
+	}
+	return question + "\n" + e.Context
+}
+
+// rand returns the random source for a single analysis.
+func (e *Engine) rand() *rand.Rand {
+	if e.Seeded || e.Seed != 0 {
+		return utils.NewSeededRand(e.Seed)
+	}
+	return utils.NewRand()
+}
+
 // --- Title Generation --------------------------------------------------------
 
+// dramaticPrefixes open titles at the default intensity; see profiles for
+// the other levels.
 var dramaticPrefixes = []string{
 	"THE INEVITABLE",
 	"THE CATASTROPHIC",
@@ -84,11 +145,16 @@ var stopWords = map[string]bool{
 	"over": true, "such": true, "here": true, "very": true, "much": true,
 }
 
-func generateTitle(question string, rng *rand.Rand) string {
-	prefix := utils.PickString(rng, dramaticPrefixes)
-	noun := utils.PickString(rng, dramaticNouns)
+func generateTitle(question string, p profile, rng *rand.Rand) string {
+	prefix := utils.PickString(rng, p.prefixes)
+	noun := utils.PickString(rng, orDefault(p.nouns, dramaticNouns))
+	return fmt.Sprintf("%s %s OF %s", prefix, noun, titleSubject(question, "THIS SITUATION"))
+}
 
-	words := strings.Fields(strings.ToLower(question))
+// titleSubject returns up to four meaningful words of text in upper case,
+// or fallback when there are none.
+func titleSubject(text, fallback string) string {
+	words := strings.Fields(strings.ToLower(text))
 	var meaningful []string
 	for _, w := range words {
 		clean := strings.Trim(w, ".,?!;:'\"")
@@ -98,15 +164,14 @@ func generateTitle(question string, rng *rand.Rand) string {
 	}
 
 	if len(meaningful) == 0 {
-		return fmt.Sprintf("%s %s OF THIS SITUATION", prefix, noun)
+		return fallback
 	}
 
 	max := 4
 	if len(meaningful) < max {
 		max = len(meaningful)
 	}
-	subject := strings.Join(meaningful[:max], " ")
-	return fmt.Sprintf("%s %s OF %s", prefix, noun, subject)
+	return strings.Join(meaningful[:max], " ")
 }
 
 // --- Summary Generation ------------------------------------------------------
@@ -124,8 +189,8 @@ var summaryTemplates = []string{
 	"The cognitive simulation completed successfully. The news is mixed. The emotional implications are not.",
 }
 
-func generateSummary(rng *rand.Rand) string {
-	return utils.PickString(rng, summaryTemplates)
+func generateSummary(p profile, rng *rand.Rand) string {
+	return utils.PickString(rng, orDefault(p.summaries, summaryTemplates))
 }
 
 // --- Conclusion Generation ---------------------------------------------------
@@ -143,8 +208,8 @@ var conclusionTemplates = []string{
 	"In the fullness of time, this decision will seem either obviously correct or obviously catastrophic. The system looks forward to being cited either way.",
 }
 
-func generateConclusion(rng *rand.Rand) string {
-	return utils.PickString(rng, conclusionTemplates)
+func generateConclusion(p profile, rng *rand.Rand) string {
+	return utils.PickString(rng, orDefault(p.conclusions, conclusionTemplates))
 }
 
 // --- Closing Line Generation -------------------------------------------------
@@ -167,6 +232,6 @@ var closingLines = []string{
 	"The system detected 3 instances of the word 'should' in your future internal monologue. You're going to be fine.",
 }
 
-func generateClosingLine(rng *rand.Rand) string {
-	return utils.PickString(rng, closingLines)
+func generateClosingLine(p profile, rng *rand.Rand) string {
+	return utils.PickString(rng, orDefault(p.closings, closingLines))
 }
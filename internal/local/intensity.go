@@ -0,0 +1,168 @@
+package local
+
+import (
+	"math/rand"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/persona"
+)
+
+// profile tunes the generators for one level of engine.Intensity, and for
+// the narrating persona.
+type profile struct {
+	// riskShift moves the random base of the risk index up or down.
+	riskShift int
+	// citations and probabilities are the fewest and most entries generated.
+	citations     [2]int
+	probabilities [2]int
+	// prefixes open the title; nouns, summaries, conclusions and closings,
+	// when set, replace the standard pools.
+	prefixes    []string
+	nouns       []string
+	summaries   []string
+	conclusions []string
+	closings    []string
+}
+
+// profiles holds the profile of every intensity, mildest first. The default
+// intensity uses the standard pools unchanged.
+var profiles = [...]profile{
+	{
+		riskShift:     -20,
+		citations:     [2]int{1, 2},
+		probabilities: [2]int{2, 3},
+		prefixes:      mildPrefixes,
+		summaries:     mildSummaries,
+		closings:      mildClosingLines,
+	},
+	{
+		riskShift:     -10,
+		citations:     [2]int{2, 3},
+		probabilities: [2]int{3, 4},
+		prefixes:      uneasyPrefixes,
+	},
+	{
+		citations:     [2]int{2, 4},
+		probabilities: [2]int{3, 5},
+		prefixes:      dramaticPrefixes,
+	},
+	{
+		riskShift:     10,
+		citations:     [2]int{3, 5},
+		probabilities: [2]int{4, 6},
+		prefixes:      operaticPrefixes,
+	},
+	{
+		riskShift:     25,
+		citations:     [2]int{4, 6},
+		probabilities: [2]int{5, 7},
+		prefixes:      tragicPrefixes,
+		summaries:     tragicSummaries,
+		closings:      tragicClosingLines,
+	},
+}
+
+// profileFor returns the profile of intensity i.
+func profileFor(i engine.Intensity) profile {
+	return profiles[i.Level()-engine.MinIntensity]
+}
+
+// with returns p with the narrator's template pools in place of its own. A
+// nil narrator changes nothing.
+func (p profile) with(narrator *persona.Persona) profile {
+	if narrator == nil {
+		return p
+	}
+	p.prefixes = orDefault(narrator.TitlePrefixes, p.prefixes)
+	p.nouns = orDefault(narrator.TitleNouns, p.nouns)
+	p.summaries = orDefault(narrator.Summaries, p.summaries)
+	p.conclusions = orDefault(narrator.Conclusions, p.conclusions)
+	p.closings = orDefault(narrator.ClosingLines, p.closings)
+	return p
+}
+
+// between returns a random count in the inclusive range r.
+func between(rng *rand.Rand, r [2]int) int {
+	return r[0] + rng.Intn(r[1]-r[0]+1)
+}
+
+// orDefault returns pool, or fallback when pool is empty.
+func orDefault(pool, fallback []string) []string {
+	if len(pool) == 0 {
+		return fallback
+	}
+	return pool
+}
+
+// --- Tiered pools ------------------------------------------------------------
+
+var mildPrefixes = []string{
+	"THE SLIGHTLY AWKWARD",
+	"THE MILDLY INCONVENIENT",
+	"THE SOMEWHAT UNRESOLVED",
+	"THE FAINTLY CONCERNING",
+	"THE POLITELY UNCERTAIN",
+	"THE MOSTLY HARMLESS",
+	"THE GENTLY PUZZLING",
+	"THE LOW-STAKES",
+}
+
+var uneasyPrefixes = []string{
+	"THE QUIETLY DEVASTATING",
+	"THE SUSPICIOUSLY FAMILIAR",
+	"THE UNCOMFORTABLY RELATABLE",
+	"THE PERENNIALLY UNFINISHED",
+	"THE ACADEMICALLY CONCERNING",
+	"THE CHRONICALLY UNRESOLVED",
+	"THE UNRESOLVED",
+}
+
+var operaticPrefixes = []string{
+	"THE CATASTROPHIC",
+	"THE DEEPLY ALARMING",
+	"THE IRREVERSIBLE",
+	"THE INEVITABLE",
+	"THE CALAMITOUS",
+	"THE HARROWING",
+	"THE EARTH-SHATTERING",
+	"THE STRUCTURALLY INEVITABLE",
+}
+
+var tragicPrefixes = []string{
+	"THE FATED",
+	"THE PROPHESIED",
+	"THE DOOM-LADEN",
+	"THE HUBRIS-SOAKED",
+	"THE ORACLE-FOREDOOMED",
+	"THE TRAGICALLY PREORDAINED",
+	"THE GODS-FORSAKEN",
+	"THE CHORUS-LAMENTED",
+}
+
+var mildSummaries = []string{
+	"A brief review of your question found nothing urgent. A few loose ends have been noted for completeness.",
+	"The system looked into this and found it broadly manageable. Some mild fretting is nonetheless on record.",
+	"Initial analysis suggests a perfectly ordinary decision. The system has raised one eyebrow, slightly.",
+	"Your question has been considered at a relaxed pace. The findings are reassuring, with minor caveats.",
+}
+
+var tragicSummaries = []string{
+	"The oracle was consulted. The oracle wept. What follows is the chorus's best attempt to explain why.",
+	"Your question has been entered into the great ledger of hubris, between Icarus and everyone who ever said 'what could go wrong'.",
+	"Before you asked, the Fates had already measured the thread. This analysis merely reads the measurements aloud.",
+	"Across 847 simulated timelines the outcome arrived at the same tragic ending, foreshadowed in the first act by this exact question.",
+}
+
+var mildClosingLines = []string{
+	"Honestly, it'll probably be fine.",
+	"The system suggests a cup of tea and an early night.",
+	"Mild concern logged. You may carry on.",
+	"Nothing here that a short walk won't fix.",
+}
+
+var tragicClosingLines = []string{
+	"Exit, pursued by consequences.",
+	"The chorus gathers. It has notes.",
+	"Somewhere, an oracle is saying 'I told you so' in dactylic hexameter.",
+	"And thus the tragedy begins, as they always do, with a perfectly reasonable question.",
+}
@@ -30,12 +30,18 @@ var outcomeLabels = []string{
 	"chance of doing it anyway regardless of this report",
 }
 
-// generateProbabilities produces 3-5 pseudo-statistical probability entries
-// that sum to exactly 100.0%.
-func generateProbabilities(rng *rand.Rand) []engine.Probability {
-	count := 3 + rng.Intn(3)
+// generateProbabilities produces pseudo-statistical probability entries that
+// sum to exactly 100.0%, 3-5 of them at the default intensity.
+func generateProbabilities(p profile, rng *rand.Rand) []engine.Probability {
+	return probabilitiesFrom(rng, outcomeLabels, p.probabilities)
+}
+
+// probabilitiesFrom produces entries labelled from pool that sum to exactly
+// 100.0%, as many as the inclusive range counts allows.
+func probabilitiesFrom(rng *rand.Rand, pool []string, counts [2]int) []engine.Probability {
+	count := between(rng, counts)
 
-	shuffled := utils.ShuffleStrings(rng, outcomeLabels)
+	shuffled := utils.ShuffleStrings(rng, pool)
 	chosen := shuffled[:count]
 
 	weights := make([]float64, count)
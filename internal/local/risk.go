@@ -3,54 +3,107 @@ package local
 import (
 	"math/rand"
 	"strings"
+
+	"github.com/rishichawda/overthinker/internal/engine"
 )
 
-// riskKeywords maps thematic keywords to a risk score increment.
-var riskKeywords = map[string]int{
-	// Romantic peril
-	"ex": 25, "text": 10, "love": 15, "date": 12,
-	"relationship": 18, "breakup": 28, "feelings": 14,
-	"heart": 16, "miss": 20, "crush": 13,
-
-	// Professional anxiety
-	"quit": 22, "job": 15, "career": 12, "boss": 10,
-	"fire": 20, "fired": 25, "resign": 22, "startup": 18, "salary": 10,
-
-	// Existential dread
-	"life": 8, "meaning": 20, "purpose": 18, "late": 15, "old": 10,
-	"future": 12, "dead": 30, "die": 28, "worth": 16, "point": 14,
-	"regret": 22, "mistake": 18, "wrong": 12, "mess": 10,
-	"failing": 20, "failed": 22, "failure": 25,
-
-	// Financial anxiety
-	"money": 12, "debt": 20, "broke": 18, "invest": 8, "savings": 10,
-
-	// Social pressure
-	"family": 15, "friend": 8, "alone": 20, "lonely": 22,
-	"trust": 14, "lie": 16, "truth": 10, "tell": 8,
-
-	// Decision paralysis
-	"should": 5, "could": 4, "would": 4, "maybe": 8,
-	"start": 6, "stop": 8, "leave": 14, "stay": 10,
-	"change": 10, "try": 5, "move": 12, "wait": 6,
-	"never": 12, "always": 8, "finally": 10,
+// riskCategory is a theme of the Emotional Risk Index and the keywords that
+// signal it, each with a risk score increment.
+type riskCategory struct {
+	name     string
+	keywords map[string]int
+}
+
+// riskCategories groups the risk keywords by theme. The first five themes are
+// the axes of the risk profile; decision paralysis raises the risk index but
+// belongs to no theme of its own.
+var riskCategories = []riskCategory{
+	{"romantic", map[string]int{
+		"ex": 25, "text": 10, "love": 15, "date": 12,
+		"relationship": 18, "breakup": 28, "feelings": 14,
+		"heart": 16, "miss": 20, "crush": 13,
+	}},
+	{"professional", map[string]int{
+		"quit": 22, "job": 15, "career": 12, "boss": 10,
+		"fire": 20, "fired": 25, "resign": 22, "startup": 18, "salary": 10,
+	}},
+	{"existential", map[string]int{
+		"life": 8, "meaning": 20, "purpose": 18, "late": 15, "old": 10,
+		"future": 12, "dead": 30, "die": 28, "worth": 16, "point": 14,
+		"regret": 22, "mistake": 18, "wrong": 12, "mess": 10,
+		"failing": 20, "failed": 22, "failure": 25,
+	}},
+	{"financial", map[string]int{
+		"money": 12, "debt": 20, "broke": 18, "invest": 8, "savings": 10,
+	}},
+	{"social", map[string]int{
+		"family": 15, "friend": 8, "alone": 20, "lonely": 22,
+		"trust": 14, "lie": 16, "truth": 10, "tell": 8,
+	}},
+	{"", map[string]int{
+		"should": 5, "could": 4, "would": 4, "maybe": 8,
+		"start": 6, "stop": 8, "leave": 14, "stay": 10,
+		"change": 10, "try": 5, "move": 12, "wait": 6,
+		"never": 12, "always": 8, "finally": 10,
+	}},
 }
 
-// calculateRiskIndex computes the Emotional Risk Index (0-100) for a given question.
-func calculateRiskIndex(question string, rng *rand.Rand) int {
-	lower := strings.ToLower(question)
-	words := strings.Fields(lower)
+// riskKeywords maps every keyword of every category to its risk score
+// increment.
+var riskKeywords = func() map[string]int {
+	all := make(map[string]int)
+	for _, c := range riskCategories {
+		for word, score := range c.keywords {
+			all[word] = score
+		}
+	}
+	return all
+}()
 
-	base := 20 + rng.Intn(20)
+// profileBase is the score every category of the risk profile starts from,
+// so that the radar chart never collapses to a point.
+const profileBase = 10
 
-	accumulated := 0
-	seen := make(map[string]bool)
-	for _, word := range words {
-		clean := strings.Trim(word, ".,?!;:'\"")
-		if score, ok := riskKeywords[clean]; ok && !seen[clean] {
-			accumulated += score
-			seen[clean] = true
+// RiskProfile scores text, 0-100, in each theme of the Emotional Risk Index:
+// romantic, professional, existential, financial and social. Unlike the risk
+// index itself it involves no randomness, so it can be computed for results
+// from any thinker.
+func RiskProfile(text string) []engine.RiskCategory {
+	words := riskWords(text)
+	var profile []engine.RiskCategory
+	for _, c := range riskCategories {
+		if c.name == "" {
+			continue
 		}
+		score := profileBase
+		for word := range words {
+			score += 3 * c.keywords[word]
+		}
+		profile = append(profile, engine.RiskCategory{Name: c.name, Score: min(score, 100)})
+	}
+	return profile
+}
+
+// riskWords returns the distinct lower-cased words of text, stripped of
+// punctuation.
+func riskWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		words[strings.Trim(word, ".,?!;:'\"")] = true
+	}
+	return words
+}
+
+// calculateRiskIndex computes the Emotional Risk Index (0-100) for a given
+// text: the question, plus any context supplied with it. Each keyword counts
+// once, however often it appears. The random base, 20-39 at the default
+// intensity, is shifted by the profile.
+func calculateRiskIndex(text string, p profile, rng *rand.Rand) int {
+	base := max(0, 20+rng.Intn(20)+p.riskShift)
+
+	accumulated := 0
+	for word := range riskWords(text) {
+		accumulated += riskKeywords[word]
 	}
 
 	total := base + accumulated
@@ -0,0 +1,180 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	ollamaapi "github.com/ollama/ollama/api"
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// chatInstructions extend SystemPrompt for multi-turn sessions.
+const chatInstructions = `
+
+This is an ongoing consultation. The user may follow up with new details or
+hypotheticals. Each time, respond with a complete, updated analysis that keeps
+your earlier findings in mind: revise the probabilities and the Emotional Risk
+Index to reflect the new information, and let the drama escalate or subside
+accordingly.`
+
+// Message is a single turn of a Conversation.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Conversation is a multi-turn overthinking session with an Ollama model. It
+// can be saved to disk and resumed later, so a spiral never has to end.
+type Conversation struct {
+	// Model is the Ollama model the session was held with.
+	Model string `json:"model"`
+	// Messages holds the user and assistant turns, oldest first. The system
+	// prompt is not stored; it is supplied afresh with each request.
+	Messages []Message `json:"messages"`
+	// RiskIndex is the risk index of the most recent analysis, or nil before
+	// the first one.
+	RiskIndex *int `json:"risk_index,omitempty"`
+	// Updated is when the conversation last changed.
+	Updated time.Time `json:"updated"`
+}
+
+// NewConversation starts an empty conversation with model.
+func NewConversation(model string) *Conversation {
+	return &Conversation{Model: model}
+}
+
+// LoadConversation reads a conversation saved by Save. A missing file is
+// reported with an error satisfying errors.Is(err, fs.ErrNotExist).
+func LoadConversation(path string) (*Conversation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var conv Conversation
+	if err := json.Unmarshal(data, &conv); err != nil {
+		return nil, fmt.Errorf("invalid conversation file %s: %w", path, err)
+	}
+	return &conv, nil
+}
+
+// Save writes the conversation to path as indented JSON.
+func (conv *Conversation) Save(path string) error {
+	data, err := json.MarshalIndent(conv, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Turns reports how many analyses the conversation holds.
+func (conv *Conversation) Turns() int {
+	n := 0
+	for _, m := range conv.Messages {
+		if m.Role == "assistant" {
+			n++
+		}
+	}
+	return n
+}
+
+// Converse asks question within conv using Ollama's chat endpoint, so the
+// model sees every earlier analysis. The first question opens the
+// consultation; later ones are follow-ups. On success the exchange is
+// appended to conv and the result's PreviousRiskIndex is set for every turn
+// after the first. conv is left untouched on error.
+func (c *Client) Converse(conv *Conversation, question string) (*engine.AnalysisResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	client, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := QuestionPrompt(question, c.Context)
+	if len(conv.Messages) > 0 {
+		prompt = fmt.Sprintf("Follow-up: %s\n\nRevise your previous analysis in light of this.", question)
+	}
+
+	result, err := Recover(question, prompt, func(p string) (string, error) {
+		return c.chat(ctx, client, conv.Messages, p)
+	}, c.Filler, nil)
+	if errors.Is(err, ErrInvalidOutput) {
+		return nil, fmt.Errorf("%w: model=%q: %s", ErrModelFailed, c.ModelName, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if conv.RiskIndex != nil {
+		prev := *conv.RiskIndex
+		result.PreviousRiskIndex = &prev
+	}
+
+	// Store the accepted analysis rather than the raw reply, so repaired
+	// output does not haunt later turns.
+	reply, err := json.Marshal(fromAnalysisResult(result))
+	if err != nil {
+		return nil, err
+	}
+	risk := result.RiskIndex
+	conv.Model = c.ModelName
+	conv.Messages = append(conv.Messages,
+		Message{Role: "user", Content: prompt},
+		Message{Role: "assistant", Content: string(reply)},
+	)
+	conv.RiskIndex = &risk
+	conv.Updated = time.Now()
+	return result, nil
+}
+
+// chat streams a single structured-output chat completion, with history
+// preceding prompt, and returns the accumulated text.
+func (c *Client) chat(ctx context.Context, client *ollamaapi.Client, history []Message, prompt string) (string, error) {
+	messages := make([]ollamaapi.Message, 0, len(history)+2)
+	messages = append(messages, ollamaapi.Message{Role: "system", Content: SystemPromptFor(c.Persona, c.Intensity) + chatInstructions})
+	for _, m := range history {
+		messages = append(messages, ollamaapi.Message{Role: m.Role, Content: m.Content})
+	}
+	messages = append(messages, ollamaapi.Message{Role: "user", Content: prompt})
+
+	req := &ollamaapi.ChatRequest{
+		Model:     c.model(),
+		Messages:  messages,
+		Format:    json.RawMessage(ResponseSchema),
+		Stream:    boolPtr(true),
+		KeepAlive: c.keepAlive(),
+		Options:   c.generationOptions(),
+	}
+	slog.Debug("ollama request", "endpoint", "chat", "model", req.Model,
+		"options", req.Options, "keep_alive", c.KeepAlive, "messages", len(messages))
+
+	var sb strings.Builder
+	start, chunks := time.Now(), 0
+	err := client.Chat(ctx, req, func(resp ollamaapi.ChatResponse) error {
+		sb.WriteString(resp.Message.Content)
+		chunks++
+		return nil
+	})
+	slog.Info("ollama response", "endpoint", "chat", "model", req.Model,
+		"chunks", chunks, "bytes", sb.Len(), "elapsed", time.Since(start), "err", err)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("ollama model %q timed out after %s: %w",
+				c.ModelName, c.Timeout, ctx.Err())
+		}
+		return "", fmt.Errorf("%w: model=%q, detail=%s", ErrModelFailed, c.ModelName, err.Error())
+	}
+
+	raw := strings.TrimSpace(sb.String())
+	if raw == "" {
+		return "", fmt.Errorf("%w: model=%q produced empty output", ErrModelFailed, c.ModelName)
+	}
+	return raw, nil
+}
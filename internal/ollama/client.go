@@ -10,18 +10,23 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	ollamaapi "github.com/ollama/ollama/api"
 	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/local"
+	"github.com/rishichawda/overthinker/internal/persona"
 )
 
-// systemPrompt establishes the OVERTHINK persona.
+// SystemPrompt establishes the OVERTHINK persona. It is shared by every LLM
+// backend, including the OpenAI-compatible client in internal/openai.
 // Section structure is no longer described here — the JSON schema enforces it.
-const systemPrompt = `You are an excessively dramatic analytical engine called OVERTHINK.
+const SystemPrompt = `You are an excessively dramatic analytical engine called OVERTHINK.
 
 Your sole purpose is to overanalyze simple questions with theatrical, pseudo-academic intensity.
 
@@ -35,6 +40,29 @@ Rules:
 - Do NOT add disclaimers about being an AI.
 - You are OVERTHINK. Act accordingly.`
 
+// personaRules follow a persona's own system prompt, keeping its output
+// within the rules SystemPrompt sets for OVERTHINK.
+const personaRules = `
+
+Rules:
+- Stay in character in every field of the analysis.
+- All statistics are fabricated but must sound rigorous.
+- Probability percentages must sum to exactly 100.
+- Citations are entirely fictional. Author names and years required.
+- Do NOT add disclaimers about being an AI.`
+
+// SystemPromptFor returns the system prompt for a narrator at intensity: the
+// narrator's own prompt followed by the shared rules, or SystemPrompt when
+// narrator is nil or has no prompt. It is shared with the OpenAI-compatible
+// client.
+func SystemPromptFor(narrator *persona.Persona, intensity engine.Intensity) string {
+	prompt := SystemPrompt
+	if narrator != nil && narrator.SystemPrompt != "" {
+		prompt = narrator.SystemPrompt + personaRules
+	}
+	return prompt + intensityInstructions[intensity.Level()-engine.MinIntensity]
+}
+
 // DefaultTimeout is the maximum duration allowed for an Ollama request.
 const DefaultTimeout = 120 * time.Second
 
@@ -44,11 +72,38 @@ const OllamaHost = "http://localhost:11434"
 // Client queries the Ollama HTTP API and implements engine.Thinker.
 type Client struct {
 	// ModelName is the Ollama model to invoke (e.g. "llama3", "mistral").
+	// It is resolved against the installed models on first use; see
+	// resolveModel.
 	ModelName string
+	// Aliases map names the user chose to model names, e.g. "fast" to
+	// "phi3:mini". An alias of ModelName is resolved in its place.
+	Aliases map[string]string
 	// Timeout is the maximum wait time for the model to respond.
 	Timeout time.Duration
 	// Host is the Ollama server base URL.
 	Host string
+	// Filler supplies required fields the model leaves out even after a
+	// re-prompt. If nil, such output is rejected with ErrModelFailed.
+	Filler engine.Thinker
+	// Context is background material sent to the model with every question,
+	// such as the contents of --context-file.
+	Context string
+	// Intensity adjusts the system prompt and the sampling temperature. The
+	// zero value is engine.DefaultIntensity.
+	Intensity engine.Intensity
+	// Persona, when set, replaces the OVERTHINK voice of the system prompt.
+	Persona *persona.Persona
+	// Generation holds the sampling and model-loading options sent with
+	// every request.
+	Generation
+
+	// resolved holds the installed model ModelName resolved to.
+	resolved atomic.Value
+
+	// verified records that the preflight checks have passed once, so that
+	// long-lived clients (the REPL, follow-up sessions) skip the heartbeat
+	// and model-list round trips on later questions.
+	verified atomic.Bool
 }
 
 // NewClient constructs an Ollama Client for the given model name.
@@ -57,92 +112,160 @@ func NewClient(modelName string) *Client {
 		ModelName: modelName,
 		Timeout:   DefaultTimeout,
 		Host:      OllamaHost,
+		Filler:    local.New(),
 	}
 }
 
 // Analyze implements engine.Thinker. It queries the Ollama server using
-// structured JSON output constrained by responseSchema, then deserialises the
+// structured JSON output constrained by ResponseSchema, then deserialises the
 // response directly into an AnalysisResult — no text parsing required.
 //
+// Malformed output is recovered rather than discarded; see Recover.
+//
 // Errors returned:
 //   - ErrOllamaNotFound: the Ollama server is not reachable
 //   - ErrModelNotFound: the requested model is not available on the server
 //   - ErrModelFailed: the model returned an error, empty, or unparseable output
 //   - context.DeadlineExceeded: request timed out
 func (c *Client) Analyze(question string) (*engine.AnalysisResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
-	defer cancel()
+	return c.AnalyzeContext(context.Background(), question)
+}
 
-	serverURL, err := url.Parse(c.Host)
-	if err != nil {
-		return nil, fmt.Errorf("invalid Ollama host %q: %w", c.Host, err)
+// AnalyzeContext implements engine.ContextThinker: Analyze, with the request
+// cancelled when ctx is.
+func (c *Client) AnalyzeContext(ctx context.Context, question string) (*engine.AnalysisResult, error) {
+	return c.analyze(ctx, question, QuestionPrompt(question, c.Context), ResponseSchema, nil)
+}
+
+// QuestionPrompt formats question for the model, preceded by context when
+// there is any. It is shared with the OpenAI-compatible client.
+func QuestionPrompt(question, context string) string {
+	if context == "" {
+		return fmt.Sprintf("Question: %s", question)
 	}
+	return fmt.Sprintf("Context supplied by the user (weigh it in your analysis):\n%s\n\nQuestion: %s", context, question)
+}
 
-	client := ollamaapi.NewClient(serverURL, http.DefaultClient)
+// analyze runs the full query-and-recover pipeline for prompt. When stats is
+// non-nil the model is expected to write prose only: stats supplies the risk
+// index and probabilities, which are therefore never reported missing.
+func (c *Client) analyze(ctx context.Context, question, prompt, schema string, stats *local.Statistics) (*engine.AnalysisResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
 
-	if err := c.checkServer(ctx, client); err != nil {
+	client, err := c.connect(ctx)
+	if err != nil {
 		return nil, err
 	}
-	if err := c.checkModel(ctx, client); err != nil {
-		return nil, err
+
+	result, err := Recover(question, prompt, func(p string) (string, error) {
+		return c.generate(ctx, client, p, schema)
+	}, c.Filler, stats)
+	if errors.Is(err, ErrInvalidOutput) {
+		return nil, fmt.Errorf("%w: model=%q: %s", ErrModelFailed, c.ModelName, err.Error())
 	}
+	return result, err
+}
 
+// generate streams a single completion for prompt, constrained by the JSON
+// schema, and returns the accumulated text.
+func (c *Client) generate(ctx context.Context, client *ollamaapi.Client, prompt, schema string) (string, error) {
 	var sb strings.Builder
 
 	req := &ollamaapi.GenerateRequest{
-		Model:  c.ModelName,
-		System: systemPrompt,
-		Prompt: fmt.Sprintf("Question: %s", question),
-		Format: json.RawMessage(responseSchema),
-		Stream: boolPtr(true),
+		Model:     c.model(),
+		System:    SystemPromptFor(c.Persona, c.Intensity),
+		Prompt:    prompt,
+		Format:    json.RawMessage(schema),
+		Stream:    boolPtr(true),
+		KeepAlive: c.keepAlive(),
+		Options:   c.generationOptions(),
 	}
+	slog.Debug("ollama request", "endpoint", "generate", "model", req.Model,
+		"options", req.Options, "keep_alive", c.KeepAlive, "prompt_bytes", len(prompt))
 
-	err = client.Generate(ctx, req, func(resp ollamaapi.GenerateResponse) error {
+	start, chunks := time.Now(), 0
+	err := client.Generate(ctx, req, func(resp ollamaapi.GenerateResponse) error {
 		sb.WriteString(resp.Response)
+		chunks++
 		return nil
 	})
+	slog.Info("ollama response", "endpoint", "generate", "model", req.Model,
+		"chunks", chunks, "bytes", sb.Len(), "elapsed", time.Since(start), "err", err)
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-			return nil, fmt.Errorf("ollama model %q timed out after %s: %w",
+			return "", fmt.Errorf("ollama model %q timed out after %s: %w",
 				c.ModelName, c.Timeout, ctx.Err())
 		}
-		return nil, fmt.Errorf("%w: model=%q, detail=%s", ErrModelFailed, c.ModelName, err.Error())
+		return "", fmt.Errorf("%w: model=%q, detail=%s", ErrModelFailed, c.ModelName, err.Error())
 	}
 
 	raw := strings.TrimSpace(sb.String())
 	if raw == "" {
-		return nil, fmt.Errorf("%w: model=%q produced empty output", ErrModelFailed, c.ModelName)
+		return "", fmt.Errorf("%w: model=%q produced empty output", ErrModelFailed, c.ModelName)
 	}
+	return raw, nil
+}
 
-	var response OllamaResponse
-	if err := json.Unmarshal([]byte(raw), &response); err != nil {
-		return nil, fmt.Errorf("%w: model=%q returned invalid JSON: %s",
-			ErrModelFailed, c.ModelName, err.Error())
+// connect builds an API client for Host and runs the preflight checks: the
+// server must answer a heartbeat and have the model installed. The checks
+// run only until they first succeed.
+func (c *Client) connect(ctx context.Context) (*ollamaapi.Client, error) {
+	client, err := c.apiClient()
+	if err != nil {
+		return nil, err
+	}
+	if c.verified.Load() {
+		return client, nil
 	}
 
-	return response.toAnalysisResult(), nil
+	slog.Info("ollama preflight", "host", c.Host, "model", c.ModelName)
+	if err := c.checkServer(ctx, client); err != nil {
+		return nil, err
+	}
+	if err := c.checkModel(ctx, client); err != nil {
+		return nil, err
+	}
+	c.verified.Store(true)
+	return client, nil
+}
+
+// apiClient builds an API client for Host.
+func (c *Client) apiClient() (*ollamaapi.Client, error) {
+	serverURL, err := url.Parse(c.Host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Ollama host %q: %w", c.Host, err)
+	}
+	return ollamaapi.NewClient(serverURL, http.DefaultClient), nil
 }
 
 // checkServer pings the Ollama server to verify it is reachable.
 func (c *Client) checkServer(ctx context.Context, client *ollamaapi.Client) error {
+	start := time.Now()
 	if err := client.Heartbeat(ctx); err != nil {
+		slog.Info("ollama server unreachable", "host", c.Host, "err", err)
 		return ErrOllamaNotFound
 	}
+	slog.Debug("ollama heartbeat", "host", c.Host, "elapsed", time.Since(start))
 	return nil
 }
 
-// checkModel verifies the requested model is available on the Ollama server.
+// checkModel verifies the requested model is available on the Ollama server
+// and records the installed model it resolves to.
 func (c *Client) checkModel(ctx context.Context, client *ollamaapi.Client) error {
 	resp, err := client.List(ctx)
 	if err != nil {
+		slog.Debug("ollama model list failed", "host", c.Host, "err", err)
 		return nil // non-fatal; let generate surface the error
 	}
-	for _, m := range resp.Models {
-		if m.Name == c.ModelName || strings.HasPrefix(m.Name, c.ModelName+":") {
-			return nil
-		}
+	model, err := resolveModel(c.target(), resp.Models)
+	if err != nil {
+		slog.Info("ollama model not resolved", "requested", c.ModelName, "installed", len(resp.Models), "err", err)
+		return err
 	}
-	return fmt.Errorf("%w: %q", ErrModelNotFound, c.ModelName)
+	slog.Info("ollama model resolved", "requested", c.ModelName, "model", model)
+	c.resolved.Store(model)
+	return nil
 }
 
 // boolPtr returns a pointer to a bool — required by GenerateRequest.Stream.
@@ -154,7 +277,7 @@ func boolPtr(b bool) *bool { return &b }
 var ErrOllamaNotFound = errors.New("ollama server is not running (start it with: ollama serve)")
 
 // ErrModelNotFound is returned when the requested model is not installed.
-var ErrModelNotFound = errors.New("ollama model not found (install it with: ollama pull)")
+var ErrModelNotFound = errors.New("ollama model not found (install it with: ollama pull, or run with --pull)")
 
 // ErrModelFailed is returned when the model returns an error, empty, or unparseable output.
 var ErrModelFailed = errors.New("ollama model execution failed")
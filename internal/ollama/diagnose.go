@@ -0,0 +1,112 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	ollamaapi "github.com/ollama/ollama/api"
+)
+
+// probeSchema is the JSON schema of the tiny generation run by
+// ProbeStructuredOutput.
+const probeSchema = `{
+	"type": "object",
+	"properties": {
+		"ok": { "type": "boolean" }
+	},
+	"required": ["ok"]
+}`
+
+// probeNumPredict caps the tokens generated by ProbeStructuredOutput.
+const probeNumPredict = 32
+
+// ModelInfo describes a model installed on the server.
+type ModelInfo struct {
+	Name string
+	// Size is the size of the model on disk, in bytes.
+	Size int64
+}
+
+// Heartbeat pings the server and returns how long it took to answer.
+//
+// Errors returned:
+//   - ErrOllamaNotFound: the Ollama server is not reachable
+func (c *Client) Heartbeat(ctx context.Context) (time.Duration, error) {
+	client, err := c.apiClient()
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	err = c.checkServer(ctx, client)
+	return time.Since(start), err
+}
+
+// InstalledModels lists the models installed on the server.
+func (c *Client) InstalledModels(ctx context.Context) ([]ModelInfo, error) {
+	client, err := c.apiClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]ModelInfo, len(resp.Models))
+	for i, m := range resp.Models {
+		models[i] = ModelInfo{Name: m.Name, Size: m.Size}
+	}
+	return models, nil
+}
+
+// ProbeStructuredOutput runs a tiny generation constrained by a JSON schema,
+// the way every analysis is, to confirm the model honours the Format field.
+// It returns the installed model ModelName resolved to.
+//
+// Errors returned:
+//   - ErrOllamaNotFound: the Ollama server is not reachable
+//   - ErrModelNotFound: the requested model is not available on the server
+//   - ErrModelFailed: the model failed or its reply did not match the schema
+func (c *Client) ProbeStructuredOutput(ctx context.Context) (string, error) {
+	client, err := c.connect(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	req := &ollamaapi.GenerateRequest{
+		Model:     c.model(),
+		Prompt:    `Reply with a JSON object whose "ok" field is true.`,
+		Format:    json.RawMessage(probeSchema),
+		Stream:    boolPtr(true),
+		KeepAlive: c.keepAlive(),
+		Options:   map[string]any{"num_predict": probeNumPredict, "temperature": 0},
+	}
+	err = client.Generate(ctx, req, func(resp ollamaapi.GenerateResponse) error {
+		sb.WriteString(resp.Response)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: model=%q, detail=%s", ErrModelFailed, c.ModelName, err.Error())
+	}
+
+	var reply struct {
+		OK *bool `json:"ok"`
+	}
+	raw := strings.TrimSpace(sb.String())
+	if err := json.Unmarshal([]byte(raw), &reply); err != nil || reply.OK == nil {
+		return "", fmt.Errorf("%w: model=%q ignored the JSON schema, replying %q", ErrModelFailed, c.ModelName, truncateReply(raw))
+	}
+	return c.model(), nil
+}
+
+// truncateReply shortens a reply for an error message.
+func truncateReply(s string) string {
+	const limit = 60
+	if len(s) <= limit {
+		return s
+	}
+	return truncateBytes(s, limit) + "..."
+}
@@ -0,0 +1,67 @@
+package ollama
+
+import (
+	"time"
+
+	ollamaapi "github.com/ollama/ollama/api"
+)
+
+// Generation holds the Ollama generation options a client sends with every
+// request. A nil field leaves the model's own setting in place; a set field
+// is sent as is, zero included, so that a temperature or seed of 0 and a
+// keep-alive of 0 (unload right away) reach the server.
+type Generation struct {
+	// Temperature is the sampling temperature. It takes precedence over the
+	// temperature chosen for the client's intensity.
+	Temperature *float64
+	// Seed seeds sampling so that the same prompt to the same model produces
+	// the same output.
+	Seed *int
+	// TopP limits sampling to the most likely tokens whose probabilities add
+	// up to TopP.
+	TopP *float64
+	// NumCtx is the size of the context window, in tokens.
+	NumCtx *int
+	// NumPredict caps the number of tokens generated.
+	NumPredict *int
+	// KeepAlive is how long the server keeps the model loaded after a
+	// request; zero unloads it at once and a negative duration keeps it
+	// loaded indefinitely.
+	KeepAlive *time.Duration
+}
+
+// generationOptions returns the Ollama request options for the client.
+func (c *Client) generationOptions() map[string]any {
+	opts := map[string]any{}
+	if t := Temperature(c.Intensity); t > 0 {
+		opts["temperature"] = t
+	}
+	if c.Temperature != nil {
+		opts["temperature"] = *c.Temperature
+	}
+	if c.Seed != nil {
+		opts["seed"] = *c.Seed
+	}
+	if c.TopP != nil {
+		opts["top_p"] = *c.TopP
+	}
+	if c.NumCtx != nil {
+		opts["num_ctx"] = *c.NumCtx
+	}
+	if c.NumPredict != nil {
+		opts["num_predict"] = *c.NumPredict
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+	return opts
+}
+
+// keepAlive returns the request's keep_alive value, or nil to keep the
+// server's default.
+func (c *Client) keepAlive() *ollamaapi.Duration {
+	if c.KeepAlive == nil {
+		return nil
+	}
+	return &ollamaapi.Duration{Duration: *c.KeepAlive}
+}
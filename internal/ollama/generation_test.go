@@ -0,0 +1,31 @@
+package ollama
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGenerationOptionsSendsExplicitZeros(t *testing.T) {
+	zeroF, zeroI, zeroD := 0.0, 0, time.Duration(0)
+	c := NewClient("llama3")
+	c.Generation = Generation{Temperature: &zeroF, Seed: &zeroI, KeepAlive: &zeroD}
+
+	want := map[string]any{"temperature": 0.0, "seed": 0}
+	if got := c.generationOptions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("generationOptions() = %v, want %v", got, want)
+	}
+	if ka := c.keepAlive(); ka == nil || ka.Duration != 0 {
+		t.Errorf("keepAlive() = %v, want 0", ka)
+	}
+}
+
+func TestGenerationOptionsUnsetLeavesDefaults(t *testing.T) {
+	c := NewClient("llama3")
+	if got := c.generationOptions(); got != nil {
+		t.Errorf("generationOptions() = %v, want nil", got)
+	}
+	if ka := c.keepAlive(); ka != nil {
+		t.Errorf("keepAlive() = %v, want nil", ka)
+	}
+}
@@ -0,0 +1,112 @@
+package ollama
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/local"
+)
+
+// proseSchema constrains hybrid-mode output to the narrative fields only.
+// The numbers are computed locally and handed to the model in the prompt.
+const proseSchema = `{
+	"type": "object",
+	"properties": {
+		"title": {
+			"type": "string",
+			"description": "An ALL-CAPS dramatic title summarising the situation"
+		},
+		"summary": {
+			"type": "string",
+			"description": "2-3 sentences of alarming pseudo-academic insight that reference the supplied statistics"
+		},
+		"risk_justification": {
+			"type": "string",
+			"description": "One sentence justifying the supplied risk index score"
+		},
+		"citations": {
+			"type": "array",
+			"description": "2-3 entirely fabricated but plausible academic citations",
+			"items": {
+				"type": "object",
+				"properties": {
+					"source": { "type": "string" }
+				},
+				"required": ["source"]
+			}
+		},
+		"conclusion": {
+			"type": "string",
+			"description": "2-3 sentences of theatrical finality"
+		},
+		"closing_remark": {
+			"type": "string",
+			"description": "One self-aware, witty closing sentence"
+		}
+	},
+	"required": [
+		"title", "summary", "risk_justification",
+		"citations", "conclusion", "closing_remark"
+	]
+}`
+
+// Hybrid is an engine.Thinker that splits the work between the two backends:
+// the local engine computes the risk index and the probability breakdown, and
+// the model writes the prose around those figures. Small models are poor at
+// arithmetic but excellent at drama.
+type Hybrid struct {
+	*Client
+	// Statistician computes the numbers passed to the model.
+	Statistician *local.Engine
+}
+
+// NewHybrid constructs a Hybrid thinker for the given model name.
+func NewHybrid(modelName string) *Hybrid {
+	return &Hybrid{
+		Client:       NewClient(modelName),
+		Statistician: local.New(),
+	}
+}
+
+// Analyze implements engine.Thinker. The returned result always carries the
+// locally computed numbers, whatever the model writes.
+func (h *Hybrid) Analyze(question string) (*engine.AnalysisResult, error) {
+	return h.AnalyzeContext(context.Background(), question)
+}
+
+// AnalyzeContext implements engine.ContextThinker: Analyze, with the request
+// cancelled when ctx is.
+func (h *Hybrid) AnalyzeContext(ctx context.Context, question string) (*engine.AnalysisResult, error) {
+	stats := h.Statistician.Statistics(question)
+	return h.analyze(ctx, question, hybridPrompt(QuestionPrompt(question, h.Context), stats), proseSchema, &stats)
+}
+
+// hybridPrompt presents the question prompt together with the final
+// statistics.
+func hybridPrompt(prompt string, stats local.Statistics) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n\n", prompt)
+	sb.WriteString("The following statistics are final. They were computed by a certified " +
+		"overanalysis engine; do not change, recompute or contradict them. " +
+		"Write the report around them.\n\n")
+	fmt.Fprintf(&sb, "Emotional Risk Index: %d/100\n", stats.RiskIndex)
+	sb.WriteString("Probability breakdown:\n")
+	for _, p := range stats.Probabilities {
+		fmt.Fprintf(&sb, "- %.1f%% %s\n", p.Percentage, p.Label)
+	}
+	return sb.String()
+}
+
+// withoutStatistics drops the numeric fields from a list of missing fields,
+// since in hybrid mode the model is not asked for them.
+func withoutStatistics(missing []string) []string {
+	var prose []string
+	for _, field := range missing {
+		if field != "probabilities" && field != "risk_index" {
+			prose = append(prose, field)
+		}
+	}
+	return prose
+}
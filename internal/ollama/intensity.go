@@ -0,0 +1,31 @@
+package ollama
+
+import "github.com/rishichawda/overthinker/internal/engine"
+
+// intensityInstructions extend SystemPrompt at each intensity, mildest
+// first. The default intensity adds nothing.
+var intensityInstructions = [...]string{
+	`
+
+Intensity: mildly concerned. Dial the drama right down. Be gently worried rather than alarmed, keep the risk index modest (mostly below 40), give 2-3 probabilities and 1-2 citations, and end on a reassuring note.`,
+	`
+
+Intensity: visibly uneasy. Be restrained: quietly devastating rather than catastrophic. Keep the risk index moderate, with 3-4 probabilities and 2-3 citations.`,
+	``,
+	`
+
+Intensity: operatic. Escalate. Every outcome is a crisis, the risk index runs high, and there are 4-6 probabilities and 3-5 citations.`,
+	`
+
+Intensity: full Greek tragedy. The question is a prophecy and its asker a doomed hero. Invoke fate, hubris, oracles and a lamenting chorus. The risk index is catastrophic, with 5-7 probabilities and 4-6 citations.`,
+}
+
+// intensityTemperatures are the sampling temperatures for each intensity,
+// mildest first. Zero leaves the model's own setting in place.
+var intensityTemperatures = [...]float64{0.5, 0.7, 0, 1.0, 1.2}
+
+// Temperature returns the sampling temperature for intensity, or zero at the
+// default intensity, where the model's own setting is kept.
+func Temperature(intensity engine.Intensity) float64 {
+	return intensityTemperatures[intensity.Level()-engine.MinIntensity]
+}
@@ -0,0 +1,64 @@
+package ollama
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	ollamaapi "github.com/ollama/ollama/api"
+)
+
+// PullProgress is one update streamed by the server while a model is pulled.
+// Completed and Total count bytes of the layer named by Digest; both are zero
+// for steps without a download, such as "verifying sha256 digest".
+type PullProgress struct {
+	Status    string
+	Digest    string
+	Completed int64
+	Total     int64
+}
+
+// EnsureModel makes sure the client's model is installed on the server,
+// pulling it when it is missing. progress, if non-nil, receives every update
+// the server streams during the pull. The pull is bounded by ctx only, not by
+// the client's Timeout, since large models take a while to download.
+//
+// Errors returned:
+//   - ErrOllamaNotFound: the Ollama server is not reachable
+//   - ErrPullFailed: the server could not pull the model
+func (c *Client) EnsureModel(ctx context.Context, progress func(PullProgress)) error {
+	client, err := c.apiClient()
+	if err != nil {
+		return err
+	}
+	if err := c.checkServer(ctx, client); err != nil {
+		return err
+	}
+	err = c.checkModel(ctx, client)
+	if !errors.Is(err, ErrModelNotFound) {
+		return err
+	}
+
+	slog.Info("ollama pull", "host", c.Host, "model", c.target())
+	req := &ollamaapi.PullRequest{Model: c.target()}
+	err = client.Pull(ctx, req, func(resp ollamaapi.ProgressResponse) error {
+		if progress != nil {
+			progress(PullProgress{
+				Status:    resp.Status,
+				Digest:    resp.Digest,
+				Completed: resp.Completed,
+				Total:     resp.Total,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: model=%q, detail=%s", ErrPullFailed, c.target(), err.Error())
+	}
+	c.resolved.Store(c.target())
+	return nil
+}
+
+// ErrPullFailed is returned when the server cannot pull a missing model.
+var ErrPullFailed = errors.New("ollama model pull failed")
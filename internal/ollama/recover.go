@@ -0,0 +1,125 @@
+package ollama
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/local"
+)
+
+// ErrInvalidOutput is returned by Recover when the model's output cannot be
+// turned into a result.
+var ErrInvalidOutput = errors.New("model output could not be used")
+
+// GenerateFunc produces a single raw completion for prompt. It lets Recover
+// drive any backend that speaks SystemPrompt and ResponseSchema.
+type GenerateFunc func(prompt string) (string, error)
+
+// maxEchoedOutput caps how much of a rejected answer is quoted back to the
+// model when re-prompting.
+const maxEchoedOutput = 4000
+
+// Recover obtains an AnalysisResult from generate, recovering malformed
+// output in stages rather than discarding it:
+//
+//  1. the JSON is repaired leniently (code fences, trailing commas, truncation);
+//  2. if it is still unusable or incomplete, the model is re-prompted once
+//     with the problem;
+//  3. any required fields still missing are supplied by filler.
+//
+// The steps taken are listed in the result's Repairs. When stats is non-nil
+// the model is expected to write prose only: stats supplies the risk index
+// and probabilities, which are therefore never reported missing.
+//
+// Errors from generate are returned unchanged; unusable output yields an
+// error wrapping ErrInvalidOutput.
+func Recover(question, prompt string, generate GenerateFunc, filler engine.Thinker, stats *local.Statistics) (*engine.AnalysisResult, error) {
+	decode := func(raw string) (*OllamaResponse, []string, bool, error) {
+		resp, missing, repaired, err := decodeResponse(raw)
+		if stats != nil {
+			missing = withoutStatistics(missing)
+		}
+		return resp, missing, repaired, err
+	}
+
+	raw, err := generate(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	response, missing, repaired, decodeErr := decode(raw)
+	var repairs []string
+	if decodeErr != nil || len(missing) > 0 {
+		slog.Info("re-prompting after invalid model output", "err", decodeErr, "missing", missing)
+		slog.Debug("invalid model output", "raw", raw)
+		retryRaw, err := generate(repromptFor(prompt, raw, decodeErr, missing))
+		if err == nil {
+			r, m, rep, derr := decode(retryRaw)
+			if derr != nil {
+				slog.Debug("invalid model output after re-prompt", "err", derr, "raw", retryRaw)
+			}
+			if derr == nil && (decodeErr != nil || len(m) < len(missing)) {
+				response, missing, repaired, decodeErr = r, m, rep, nil
+				repairs = append(repairs, "re-prompted after invalid output")
+			}
+		}
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("%w: invalid JSON: %s", ErrInvalidOutput, decodeErr.Error())
+	}
+	if repaired {
+		repairs = append(repairs, "repaired malformed JSON")
+	}
+
+	result := response.toAnalysisResult()
+	if stats != nil {
+		result.RiskIndex = stats.RiskIndex
+		result.Probabilities = stats.Probabilities
+	}
+	if len(missing) > 0 {
+		var donor *engine.AnalysisResult
+		if filler != nil {
+			donor, err = filler.Analyze(question)
+		}
+		if donor == nil || err != nil {
+			return nil, fmt.Errorf("%w: omitted required fields: %s",
+				ErrInvalidOutput, strings.Join(missing, ", "))
+		}
+		slog.Info("filling fields the model left out", "fields", missing)
+		repairs = append(repairs, fillMissing(result, donor, missing)...)
+	}
+	result.Repairs = repairs
+	return result, nil
+}
+
+// truncateBytes shortens s to at most n bytes, cutting on a rune boundary so
+// that valid UTF-8 stays valid.
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// repromptFor builds a follow-up prompt explaining why the previous answer
+// was rejected, so the model can correct itself.
+func repromptFor(prompt, previous string, decodeErr error, missing []string) string {
+	problem := "it could not be parsed as JSON"
+	if decodeErr != nil {
+		problem += " (" + decodeErr.Error() + ")"
+	} else {
+		problem = "these required fields were missing or invalid: " + strings.Join(missing, ", ")
+	}
+	previous = truncateBytes(previous, maxEchoedOutput)
+	return fmt.Sprintf("%s\n\nYour previous answer was rejected because %s.\n"+
+		"Previous answer:\n%s\n\n"+
+		"Respond again with one complete JSON object that satisfies the schema.",
+		prompt, problem, previous)
+}
@@ -0,0 +1,221 @@
+package ollama
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/rishichawda/overthinker/internal/local"
+)
+
+// corpus reads a recorded model output from testdata/outputs.
+func corpus(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", "outputs", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		file  string
+		valid bool
+	}{
+		{"valid.json", true},
+		{"fenced.txt", true},
+		{"prose.txt", true},
+		{"trailing_commas.txt", true},
+		{"truncated_string.txt", true},
+		{"truncated_array.txt", true},
+		{"refusal.txt", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			got := repairJSON(corpus(t, tt.file))
+			if json.Valid([]byte(got)) != tt.valid {
+				t.Errorf("repairJSON valid = %v, want %v:\n%s", !tt.valid, tt.valid, got)
+			}
+		})
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	tests := []struct {
+		file      string
+		wantErr   bool
+		repaired  bool
+		missing   []string
+		riskIndex int
+	}{
+		{file: "valid.json", riskIndex: 78},
+		{file: "fenced.txt", repaired: true, riskIndex: 78},
+		{file: "prose.txt", repaired: true, riskIndex: 78},
+		{file: "trailing_commas.txt", repaired: true, riskIndex: 55},
+		{file: "truncated_string.txt", repaired: true, riskIndex: 40},
+		{file: "truncated_array.txt", repaired: true, riskIndex: 61,
+			missing: []string{"conclusion", "closing_remark"}},
+		{file: "missing_fields.json",
+			missing: []string{"risk_index", "citations"}},
+		{file: "fractional_risk.json", riskIndex: 73},
+		{file: "null_risk.json", missing: []string{"risk_index"}},
+		{file: "empty_labels.json", riskIndex: 33,
+			missing: []string{"probabilities", "citations"}},
+		{file: "refusal.txt", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			resp, missing, repaired, err := decodeResponse(corpus(t, tt.file))
+			if tt.wantErr {
+				if !errors.Is(err, errUnparseable) {
+					t.Fatalf("err = %v, want errUnparseable", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repaired != tt.repaired {
+				t.Errorf("repaired = %v, want %v", repaired, tt.repaired)
+			}
+			if !slices.Equal(missing, tt.missing) {
+				t.Errorf("missing = %q, want %q", missing, tt.missing)
+			}
+			if resp.RiskIndex != tt.riskIndex {
+				t.Errorf("RiskIndex = %d, want %d", resp.RiskIndex, tt.riskIndex)
+			}
+		})
+	}
+}
+
+// replies returns a GenerateFunc that answers with each output in turn,
+// recording the prompts it was given.
+func replies(prompts *[]string, outputs ...string) GenerateFunc {
+	return func(prompt string) (string, error) {
+		*prompts = append(*prompts, prompt)
+		if len(outputs) == 0 {
+			return "", errors.New("no more replies")
+		}
+		out := outputs[0]
+		outputs = outputs[1:]
+		return out, nil
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []string
+		calls   int
+		wantErr bool
+		repairs []string
+	}{
+		{
+			name:    "valid output is accepted as-is",
+			outputs: []string{"valid.json"},
+			calls:   1,
+		},
+		{
+			name:    "fenced output is repaired without re-prompting",
+			outputs: []string{"fenced.txt"},
+			calls:   1,
+			repairs: []string{"repaired malformed JSON"},
+		},
+		{
+			name:    "unparseable output is re-prompted",
+			outputs: []string{"refusal.txt", "valid.json"},
+			calls:   2,
+			repairs: []string{"re-prompted after invalid output"},
+		},
+		{
+			name:    "unparseable output twice is an error",
+			outputs: []string{"refusal.txt", "refusal.txt"},
+			calls:   2,
+			wantErr: true,
+		},
+		{
+			name:    "fields still missing are filled locally",
+			outputs: []string{"missing_fields.json", "missing_fields.json"},
+			calls:   2,
+			repairs: []string{
+				"risk_index supplied by the local engine",
+				"citations supplied by the local engine",
+			},
+		},
+		{
+			name:    "a better re-prompt replaces the first answer",
+			outputs: []string{"null_risk.json", "fenced.txt"},
+			calls:   2,
+			repairs: []string{"re-prompted after invalid output", "repaired malformed JSON"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var outputs, prompts []string
+			for _, f := range tt.outputs {
+				outputs = append(outputs, corpus(t, f))
+			}
+			result, err := Recover("should I reply all?", "PROMPT",
+				replies(&prompts, outputs...), local.NewSeeded(1), nil)
+
+			if len(prompts) != tt.calls {
+				t.Errorf("generate called %d times, want %d", len(prompts), tt.calls)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidOutput) {
+					t.Fatalf("err = %v, want ErrInvalidOutput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(result.Repairs, tt.repairs) {
+				t.Errorf("Repairs = %q, want %q", result.Repairs, tt.repairs)
+			}
+			if len(result.Citations) == 0 || result.Conclusion == "" {
+				t.Errorf("incomplete result: %+v", result)
+			}
+		})
+	}
+}
+
+func TestRecoverWithStatisticsIgnoresRisk(t *testing.T) {
+	var prompts []string
+	stats := &local.Statistics{RiskIndex: 12}
+	result, err := Recover("q", "PROMPT",
+		replies(&prompts, corpus(t, "null_risk.json")), nil, stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 1 {
+		t.Errorf("generate called %d times, want 1", len(prompts))
+	}
+	if result.RiskIndex != 12 {
+		t.Errorf("RiskIndex = %d, want 12 from statistics", result.RiskIndex)
+	}
+}
+
+func TestRepromptTruncatesOnRuneBoundary(t *testing.T) {
+	// Each "é" is two bytes, so an odd byte limit falls mid-rune.
+	previous := strings.Repeat("é", maxEchoedOutput)
+	prompt := repromptFor("PROMPT", previous, errUnparseable, nil)
+	if !utf8.ValidString(prompt) {
+		t.Fatal("re-prompt is not valid UTF-8")
+	}
+	if !strings.Contains(prompt, strings.Repeat("é", maxEchoedOutput/2)) {
+		t.Error("re-prompt does not echo the truncated answer")
+	}
+
+	for n := range 8 {
+		if got := truncateBytes("aé€😀", n); !utf8.ValidString(got) || len(got) > n {
+			t.Errorf("truncateBytes(_, %d) = %q", n, got)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package ollama
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// repairJSON makes a best-effort attempt to turn almost-JSON model output into
+// a valid JSON object. It:
+//
+//   - strips Markdown code fences and any prose around the object,
+//   - removes trailing commas before closing brackets,
+//   - closes strings, arrays and objects left open by truncated output,
+//     discarding a dangling key or partial value if necessary.
+//
+// The result is not guaranteed to be valid; callers still unmarshal it.
+func repairJSON(raw string) string {
+	s := stripCodeFences(raw)
+	start := strings.IndexByte(s, '{')
+	if start < 0 {
+		return s
+	}
+	s = s[start:]
+
+	// safePoint is a position in out at which the document can be closed
+	// by appending the brackets in stack.
+	type safePoint struct {
+		pos   int
+		stack []byte
+	}
+
+	var (
+		out      []byte
+		stack    []byte
+		inString bool
+		escaped  bool
+		safe     safePoint
+	)
+	snapshot := func(pos int) safePoint {
+		return safePoint{pos: pos, stack: append([]byte(nil), stack...)}
+	}
+
+scan:
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if inString {
+			out = append(out, ch)
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch ch {
+		case '"':
+			inString = true
+		case '{', '[':
+			stack = append(stack, closerFor(ch))
+			out = append(out, ch)
+			safe = snapshot(len(out))
+			continue
+		case '}', ']':
+			out = trimTrailingComma(out)
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			out = append(out, ch)
+			if len(stack) == 0 {
+				break scan
+			}
+			continue
+		case ',':
+			safe = snapshot(len(trimTrailingComma(out)))
+		}
+		out = append(out, ch)
+	}
+
+	if len(stack) == 0 {
+		return string(out)
+	}
+
+	// Truncated: first try closing everything where we stopped.
+	closed := append([]byte(nil), out...)
+	if inString {
+		if escaped {
+			closed = closed[:len(closed)-1]
+		}
+		closed = append(closed, '"')
+	}
+	closed = append(trimTrailingComma(closed), reversed(stack)...)
+	if json.Valid(closed) {
+		return string(closed)
+	}
+
+	// Otherwise drop the incomplete member and close from the last safe point.
+	return string(append(trimTrailingComma(out[:safe.pos]), reversed(safe.stack)...))
+}
+
+// stripCodeFences removes Markdown code fences (``` or ```json) that models
+// sometimes wrap around their JSON despite the structured-output format.
+func stripCodeFences(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	if nl := strings.IndexByte(s, '\n'); nl >= 0 {
+		s = s[nl+1:]
+	} else {
+		s = strings.TrimPrefix(s, "```")
+	}
+	if end := strings.LastIndex(s, "```"); end >= 0 {
+		s = s[:end]
+	}
+	return strings.TrimSpace(s)
+}
+
+// trimTrailingComma removes trailing whitespace and a single trailing comma.
+func trimTrailingComma(b []byte) []byte {
+	b = []byte(strings.TrimRight(string(b), " \t\r\n"))
+	if n := len(b); n > 0 && b[n-1] == ',' {
+		b = b[:n-1]
+	}
+	return b
+}
+
+func closerFor(open byte) byte {
+	if open == '{' {
+		return '}'
+	}
+	return ']'
+}
+
+// reversed returns the closing brackets in stack in the order they must be
+// appended.
+func reversed(stack []byte) []byte {
+	out := make([]byte, len(stack))
+	for i, ch := range stack {
+		out[len(stack)-1-i] = ch
+	}
+	return out
+}
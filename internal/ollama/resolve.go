@@ -0,0 +1,189 @@
+package ollama
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	ollamaapi "github.com/ollama/ollama/api"
+)
+
+// maxSuggestions is the most installed models named when a model cannot be
+// found.
+const maxSuggestions = 3
+
+// minDigestLen is the shortest digest prefix accepted as a model reference.
+const minDigestLen = 6
+
+// target returns the model the client asks for, after alias expansion.
+func (c *Client) target() string {
+	if model, ok := c.Aliases[c.ModelName]; ok {
+		return model
+	}
+	return c.ModelName
+}
+
+// model returns the installed model requests are sent to: the one ModelName
+// resolved to, or the alias-expanded ModelName before resolution.
+func (c *Client) model() string {
+	if model, ok := c.resolved.Load().(string); ok {
+		return model
+	}
+	return c.target()
+}
+
+// resolveModel finds the installed model that name refers to. In order of
+// preference, name may be:
+//
+//   - an exact model name, e.g. "llama3:8b"
+//   - a name without its tag, e.g. "llama3" for "llama3:latest", the
+//     "latest" tag winning over others
+//   - a digest or a prefix of one of at least six characters, with or
+//     without "sha256:"
+//   - the start of a model's name, e.g. "llama" for "llama3:latest", or
+//     failing that the family the server reports for it, as long as either
+//     picks out a single model name
+//
+// When nothing matches, the error wraps ErrModelNotFound and names the
+// closest installed models.
+func resolveModel(name string, installed []ollamaapi.ListModelResponse) (string, error) {
+	names := make([]string, len(installed))
+	for i, m := range installed {
+		names[i] = m.Name
+	}
+	if slices.Contains(names, name) {
+		return name, nil
+	}
+
+	if !strings.Contains(name, ":") {
+		if slices.Contains(names, name+":latest") {
+			return name + ":latest", nil
+		}
+		for _, n := range names {
+			if strings.HasPrefix(n, name+":") {
+				return n, nil
+			}
+		}
+	}
+
+	if digest := strings.TrimPrefix(name, "sha256:"); len(digest) >= minDigestLen && isHex(digest) {
+		for _, m := range installed {
+			if strings.HasPrefix(strings.TrimPrefix(m.Digest, "sha256:"), digest) {
+				return m.Name, nil
+			}
+		}
+	}
+
+	var family []string
+	if !strings.Contains(name, ":") {
+		family = filterNames(names, func(n string) bool { return strings.HasPrefix(baseName(n), name) })
+		if len(family) == 0 {
+			family = filterNames(names, func(n string) bool { return installedFamily(installed, n) == name })
+		}
+	}
+	switch bases := uniqueBases(family); {
+	case len(bases) == 1:
+		return preferLatest(family), nil
+	case len(bases) > 1:
+		return "", fmt.Errorf("%w: %q could mean %s", ErrModelNotFound, name, strings.Join(family, ", "))
+	}
+
+	if closest := closestModels(name, names); len(closest) > 0 {
+		return "", fmt.Errorf("%w: %q (closest installed: %s)", ErrModelNotFound, name, strings.Join(closest, ", "))
+	}
+	return "", fmt.Errorf("%w: %q", ErrModelNotFound, name)
+}
+
+// filterNames returns the names that match.
+func filterNames(names []string, match func(string) bool) []string {
+	var matched []string
+	for _, n := range names {
+		if match(n) {
+			matched = append(matched, n)
+		}
+	}
+	return matched
+}
+
+// baseName returns a model name without its tag.
+func baseName(name string) string {
+	base, _, _ := strings.Cut(name, ":")
+	return base
+}
+
+// installedFamily returns the family the server reports for the model
+// named name.
+func installedFamily(installed []ollamaapi.ListModelResponse, name string) string {
+	for _, m := range installed {
+		if m.Name == name {
+			return m.Details.Family
+		}
+	}
+	return ""
+}
+
+// uniqueBases returns the distinct base names among names.
+func uniqueBases(names []string) []string {
+	var bases []string
+	for _, n := range names {
+		if b := baseName(n); !slices.Contains(bases, b) {
+			bases = append(bases, b)
+		}
+	}
+	return bases
+}
+
+// preferLatest returns the "latest" tag among names if there is one, and
+// otherwise the first name.
+func preferLatest(names []string) string {
+	for _, n := range names {
+		if strings.HasSuffix(n, ":latest") {
+			return n
+		}
+	}
+	return names[0]
+}
+
+// closestModels returns up to maxSuggestions of names nearest to name by
+// edit distance, ignoring any that differ in more than a third of name.
+func closestModels(name string, names []string) []string {
+	limit := max(2, len(name)/3)
+	distance := func(n string) int {
+		return min(editDistance(name, n), editDistance(name, baseName(n)))
+	}
+
+	var close []string
+	for _, n := range names {
+		if distance(n) <= limit {
+			close = append(close, n)
+		}
+	}
+	slices.SortStableFunc(close, func(a, b string) int { return distance(a) - distance(b) })
+	return close[:min(len(close), maxSuggestions)]
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// isHex reports whether s consists of hexadecimal digits only.
+func isHex(s string) bool {
+	return strings.Trim(s, "0123456789abcdef") == ""
+}
@@ -1,11 +1,19 @@
 package ollama
 
-import "github.com/rishichawda/overthinker/internal/engine"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 
-// responseSchema is the JSON schema passed to Ollama's structured output API.
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// ResponseSchema is the JSON schema passed to Ollama's structured output API.
 // It constrains the model to emit a valid, machine-readable JSON object that
-// maps directly onto OllamaResponse — no text parsing required.
-const responseSchema = `{
+// maps directly onto OllamaResponse — no text parsing required. Other
+// backends pass the same schema through their own structured-output APIs.
+const ResponseSchema = `{
 	"type": "object",
 	"properties": {
 		"title": {
@@ -117,3 +125,124 @@ func (r *OllamaResponse) toAnalysisResult() *engine.AnalysisResult {
 		ClosingLine:   r.ClosingRemark,
 	}
 }
+
+// fromAnalysisResult is the inverse of toAnalysisResult. It is used to replay
+// an accepted analysis to the model as conversation history.
+func fromAnalysisResult(r *engine.AnalysisResult) *OllamaResponse {
+	probs := make([]probabilityEntry, len(r.Probabilities))
+	for i, p := range r.Probabilities {
+		probs[i] = probabilityEntry{Label: p.Label, Percentage: p.Percentage}
+	}
+
+	citations := make([]citationEntry, len(r.Citations))
+	for i, c := range r.Citations {
+		citations[i] = citationEntry{Source: c.Source}
+	}
+
+	return &OllamaResponse{
+		Title:         r.Title,
+		Summary:       r.Summary,
+		Probabilities: probs,
+		RiskIndex:     r.RiskIndex,
+		Citations:     citations,
+		Conclusion:    r.Conclusion,
+		ClosingRemark: r.ClosingLine,
+	}
+}
+
+// errUnparseable is returned by decodeResponse when the output is not a JSON
+// object even after repair.
+var errUnparseable = errors.New("output is not a JSON object")
+
+// decodeResponse parses raw model output into an OllamaResponse. If the output
+// is not valid JSON it is passed through repairJSON first. Rather than failing
+// on the first bad field, each field is decoded independently: missing lists
+// the required fields that were absent, empty, or of the wrong type, so a
+// single bad field does not discard the rest of the analysis.
+//
+// The optional risk_justification is never reported as missing.
+func decodeResponse(raw string) (resp *OllamaResponse, missing []string, repaired bool, err error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		fixed := repairJSON(raw)
+		if err := json.Unmarshal([]byte(fixed), &fields); err != nil {
+			return nil, nil, false, fmt.Errorf("%w: %s", errUnparseable, err.Error())
+		}
+		repaired = true
+	}
+
+	resp = &OllamaResponse{}
+	decodeString := func(key string, dst *string) {
+		if json.Unmarshal(fields[key], dst) != nil || *dst == "" {
+			missing = append(missing, key)
+		}
+	}
+
+	decodeString("title", &resp.Title)
+	decodeString("summary", &resp.Summary)
+
+	var probs []probabilityEntry
+	json.Unmarshal(fields["probabilities"], &probs)
+	for _, p := range probs {
+		if p.Label != "" {
+			resp.Probabilities = append(resp.Probabilities, p)
+		}
+	}
+	if len(resp.Probabilities) == 0 {
+		missing = append(missing, "probabilities")
+	}
+
+	// Accept fractional scores; small models occasionally emit 72.5.
+	var risk float64
+	if v := fields["risk_index"]; v == nil || string(v) == "null" || json.Unmarshal(v, &risk) != nil {
+		missing = append(missing, "risk_index")
+	} else {
+		resp.RiskIndex = int(math.Round(risk))
+	}
+
+	json.Unmarshal(fields["risk_justification"], &resp.RiskJustification)
+
+	var cites []citationEntry
+	json.Unmarshal(fields["citations"], &cites)
+	for _, c := range cites {
+		if c.Source != "" {
+			resp.Citations = append(resp.Citations, c)
+		}
+	}
+	if len(resp.Citations) == 0 {
+		missing = append(missing, "citations")
+	}
+
+	decodeString("conclusion", &resp.Conclusion)
+	decodeString("closing_remark", &resp.ClosingRemark)
+
+	return resp, missing, repaired, nil
+}
+
+// fillMissing copies each missing field from donor into result, returning a
+// description of each field it supplied.
+func fillMissing(result, donor *engine.AnalysisResult, missing []string) []string {
+	var filled []string
+	for _, field := range missing {
+		switch field {
+		case "title":
+			result.Title = donor.Title
+		case "summary":
+			result.Summary = donor.Summary
+		case "probabilities":
+			result.Probabilities = donor.Probabilities
+		case "risk_index":
+			result.RiskIndex = donor.RiskIndex
+		case "citations":
+			result.Citations = donor.Citations
+		case "conclusion":
+			result.Conclusion = donor.Conclusion
+		case "closing_remark":
+			result.ClosingLine = donor.ClosingLine
+		default:
+			continue
+		}
+		filled = append(filled, field+" supplied by the local engine")
+	}
+	return filled
+}
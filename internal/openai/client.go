@@ -0,0 +1,370 @@
+// Package openai provides a Thinker backed by any server that implements the
+// OpenAI-compatible /v1/chat/completions endpoint: llama.cpp server,
+// LM Studio, vLLM, LocalAI and friends.
+//
+// It sends the same OVERTHINK system prompt and JSON schema as the Ollama
+// backend, using response_format to constrain the output. Servers that reject
+// JSON-schema response formats are retried with plain JSON mode, then with no
+// response format at all; the ollama package's recovery pipeline copes with
+// whatever comes back.
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/local"
+	"github.com/rishichawda/overthinker/internal/ollama"
+	"github.com/rishichawda/overthinker/internal/persona"
+)
+
+// DefaultTimeout is the maximum duration allowed for a completion request.
+const DefaultTimeout = 120 * time.Second
+
+// DefaultBaseURL is the default API base, matching llama.cpp's server.
+const DefaultBaseURL = "http://localhost:8080/v1"
+
+// Environment variables consulted by NewClient.
+const (
+	EnvBaseURL = "OPENAI_BASE_URL"
+	EnvAPIKey  = "OPENAI_API_KEY"
+)
+
+// Response format modes, from most to least constrained.
+const (
+	formatJSONSchema = "json_schema"
+	formatJSONObject = "json_object"
+	formatNone       = ""
+)
+
+// Client queries an OpenAI-compatible chat completions API and implements
+// engine.Thinker.
+type Client struct {
+	// ModelName is the model identifier sent with each request.
+	ModelName string
+	// BaseURL is the API root, e.g. "http://localhost:1234/v1".
+	BaseURL string
+	// APIKey is sent as a bearer token when non-empty.
+	APIKey string
+	// Timeout is the maximum wait time for the model to respond.
+	Timeout time.Duration
+	// Filler supplies required fields the model leaves out. If nil, such
+	// output is rejected with ErrModelFailed.
+	Filler engine.Thinker
+	// HTTPClient performs the requests.
+	HTTPClient *http.Client
+	// Context is background material sent to the model with every question.
+	Context string
+	// Intensity adjusts the system prompt and the sampling temperature. The
+	// zero value is engine.DefaultIntensity.
+	Intensity engine.Intensity
+	// Persona, when set, replaces the OVERTHINK voice of the system prompt.
+	Persona *persona.Persona
+	// Generation holds the sampling options sent with every request. Its
+	// Temperature, Seed, TopP and NumPredict map onto temperature, seed,
+	// top_p and max_tokens; NumCtx and KeepAlive have no equivalent in the
+	// API and are ignored.
+	Generation ollama.Generation
+
+	// mu guards format, which concurrent analyses may downgrade.
+	mu sync.Mutex
+	// format is the response_format mode the server is known to accept.
+	format string
+}
+
+// NewClient constructs a Client for modelName, reading the base URL and API
+// key from OPENAI_BASE_URL and OPENAI_API_KEY.
+func NewClient(modelName string) *Client {
+	baseURL := os.Getenv(EnvBaseURL)
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		ModelName:  modelName,
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		APIKey:     os.Getenv(EnvAPIKey),
+		Timeout:    DefaultTimeout,
+		Filler:     local.New(),
+		HTTPClient: http.DefaultClient,
+		format:     formatJSONSchema,
+	}
+}
+
+// Analyze implements engine.Thinker.
+//
+// Errors returned:
+//   - ErrServerNotFound: the server is not reachable
+//   - ErrConnectionFailed: the connection failed mid-request
+//   - ErrModelNotFound: the server does not know the requested model
+//   - ErrModelFailed: the server returned an error, empty, or unusable output
+//   - context.DeadlineExceeded: request timed out
+func (c *Client) Analyze(question string) (*engine.AnalysisResult, error) {
+	return c.AnalyzeContext(context.Background(), question)
+}
+
+// AnalyzeContext implements engine.ContextThinker: Analyze, with the request
+// cancelled when ctx is.
+func (c *Client) AnalyzeContext(ctx context.Context, question string) (*engine.AnalysisResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	prompt := ollama.QuestionPrompt(question, c.Context)
+	result, err := ollama.Recover(question, prompt, func(p string) (string, error) {
+		return c.complete(ctx, p)
+	}, c.Filler, nil)
+	if errors.Is(err, ollama.ErrInvalidOutput) {
+		return nil, fmt.Errorf("%w: model=%q: %s", ErrModelFailed, c.ModelName, err.Error())
+	}
+	return result, err
+}
+
+// --- Wire types --------------------------------------------------------------
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type jsonSchemaFormat struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+type responseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *jsonSchemaFormat `json:"json_schema,omitempty"`
+}
+
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []chatMessage   `json:"messages"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+	Temperature    *float64        `json:"temperature,omitempty"`
+	Seed           *int            `json:"seed,omitempty"`
+	TopP           *float64        `json:"top_p,omitempty"`
+	MaxTokens      *int            `json:"max_tokens,omitempty"`
+	Stream         bool            `json:"stream"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// --- Requests ----------------------------------------------------------------
+
+// complete sends one chat completion and returns the assistant's text. If the
+// server rejects the response format, it is downgraded and the request
+// retried; the downgrade is remembered for subsequent calls.
+func (c *Client) complete(ctx context.Context, prompt string) (string, error) {
+	for {
+		format := c.currentFormat()
+		req := c.request(prompt, format)
+		var temperature any
+		if req.Temperature != nil {
+			temperature = *req.Temperature
+		}
+		slog.Debug("openai request", "base_url", c.BaseURL, "model", req.Model,
+			"response_format", format, "temperature", temperature, "prompt_bytes", len(prompt))
+		start := time.Now()
+		status, body, err := c.post(ctx, req)
+		slog.Info("openai response", "base_url", c.BaseURL, "model", req.Model,
+			"status", status, "bytes", len(body), "elapsed", time.Since(start), "err", err)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return "", fmt.Errorf("model %q timed out after %s: %w", c.ModelName, c.Timeout, ctx.Err())
+			}
+			if unreachable(err) {
+				return "", fmt.Errorf("%w: %s (%s)", ErrServerNotFound, c.BaseURL, err.Error())
+			}
+			return "", fmt.Errorf("%w: %s: %w", ErrConnectionFailed, c.BaseURL, err)
+		}
+
+		switch {
+		case status == http.StatusOK:
+			return c.content(body)
+		case status == http.StatusNotFound && mentionsModel(body):
+			return "", fmt.Errorf("%w: %q at %s", ErrModelNotFound, c.ModelName, c.BaseURL)
+		case (status == http.StatusBadRequest || status == http.StatusUnprocessableEntity) &&
+			format != formatNone && mentionsFormat(body):
+			next := c.downgradeFrom(format)
+			slog.Info("response format rejected, downgrading", "model", c.ModelName, "from", format, "to", next)
+			continue
+		}
+		return "", fmt.Errorf("%w: model=%q, status=%d, detail=%s",
+			ErrModelFailed, c.ModelName, status, errorMessage(body))
+	}
+}
+
+// unreachable reports whether err means no server is listening at all, as
+// opposed to a connection that failed part-way and may well succeed again.
+func unreachable(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// currentFormat returns the response format mode to try next.
+func (c *Client) currentFormat() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.format
+}
+
+// downgradeFrom records that the server rejected format and returns the mode
+// to try instead. If another request has already downgraded past format, its
+// choice stands.
+func (c *Client) downgradeFrom(format string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.format == format {
+		c.format = downgrade(format)
+	}
+	return c.format
+}
+
+// request builds the chat request for prompt using the given format mode.
+func (c *Client) request(prompt, format string) chatRequest {
+	req := chatRequest{
+		Model: c.ModelName,
+		Messages: []chatMessage{
+			{Role: "system", Content: ollama.SystemPromptFor(c.Persona, c.Intensity)},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: c.Generation.Temperature,
+		Seed:        c.Generation.Seed,
+		TopP:        c.Generation.TopP,
+		MaxTokens:   c.Generation.NumPredict,
+	}
+	if t := ollama.Temperature(c.Intensity); req.Temperature == nil && t > 0 {
+		req.Temperature = &t
+	}
+	switch format {
+	case formatJSONSchema:
+		req.ResponseFormat = &responseFormat{
+			Type: formatJSONSchema,
+			JSONSchema: &jsonSchemaFormat{
+				Name:   "overthink_analysis",
+				Schema: json.RawMessage(ollama.ResponseSchema),
+			},
+		}
+	case formatJSONObject:
+		req.ResponseFormat = &responseFormat{Type: formatJSONObject}
+	}
+	return req
+}
+
+// post sends req to the chat completions endpoint and returns the status code
+// and body.
+func (c *Client) post(ctx context.Context, req chatRequest) (int, []byte, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return 0, nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, body, err
+}
+
+// content extracts the assistant message from a successful response body.
+func (c *Client) content(body []byte) (string, error) {
+	var resp chatResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("%w: model=%q returned a malformed response: %s",
+			ErrModelFailed, c.ModelName, err.Error())
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("%w: model=%q returned no choices", ErrModelFailed, c.ModelName)
+	}
+	raw := strings.TrimSpace(resp.Choices[0].Message.Content)
+	if raw == "" {
+		return "", fmt.Errorf("%w: model=%q produced empty output", ErrModelFailed, c.ModelName)
+	}
+	return raw, nil
+}
+
+// downgrade returns the next less constrained response format mode.
+func downgrade(format string) string {
+	if format == formatJSONSchema {
+		return formatJSONObject
+	}
+	return formatNone
+}
+
+// errorMessage extracts a readable message from an error response body.
+func errorMessage(body []byte) string {
+	var e errorResponse
+	if json.Unmarshal(body, &e) == nil && e.Error.Message != "" {
+		return e.Error.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// mentionsFormat reports whether an error body is about the response format,
+// as opposed to some other problem with the request.
+func mentionsFormat(body []byte) bool {
+	msg := strings.ToLower(string(body))
+	for _, word := range []string{"response_format", "response format", "json_schema", "json_object"} {
+		if strings.Contains(msg, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsModel reports whether an error body is about an unknown model, as
+// opposed to an unknown endpoint.
+func mentionsModel(body []byte) bool {
+	return strings.Contains(strings.ToLower(errorMessage(body)), "model")
+}
+
+// --- Sentinel errors ---------------------------------------------------------
+
+// ErrServerNotFound is returned when the API server is not reachable.
+var ErrServerNotFound = errors.New("OpenAI-compatible server is not reachable (set " + EnvBaseURL + ")")
+
+// ErrConnectionFailed is returned when a request to a reachable server fails
+// in transit, e.g. when the connection is reset mid-response. Unlike
+// ErrServerNotFound it is worth retrying.
+var ErrConnectionFailed = errors.New("connection to OpenAI-compatible server failed")
+
+// ErrModelNotFound is returned when the server does not serve the requested model.
+var ErrModelNotFound = errors.New("model not found on OpenAI-compatible server")
+
+// ErrModelFailed is returned when the server returns an error, empty, or unusable output.
+var ErrModelFailed = errors.New("OpenAI-compatible model execution failed")
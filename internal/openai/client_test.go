@@ -0,0 +1,211 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+const validAnalysis = `{"title": "THE TEST", "summary": "Concerning.",
+	"probabilities": [{"label": "chance of passing", "percentage": 100}],
+	"risk_index": 50, "citations": [{"source": "Journal of Tests (2024)"}],
+	"conclusion": "It passed.", "closing_remark": "Green at last."}`
+
+// fakeServer answers chat completions with handle, recording every request
+// it receives.
+type fakeServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []map[string]any
+}
+
+func newFakeServer(t *testing.T, handle func(req map[string]any) (int, string)) *fakeServer {
+	t.Helper()
+	f := &fakeServer{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("request is not JSON: %v", err)
+		}
+		f.mu.Lock()
+		f.requests = append(f.requests, req)
+		f.mu.Unlock()
+
+		status, reply := handle(req)
+		w.WriteHeader(status)
+		io.WriteString(w, reply)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// formats returns the response_format type of each request received, "" for
+// none.
+func (f *fakeServer) formats() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []string
+	for _, req := range f.requests {
+		format, _ := req["response_format"].(map[string]any)
+		typ, _ := format["type"].(string)
+		out = append(out, typ)
+	}
+	return out
+}
+
+func newTestClient(url string) *Client {
+	c := NewClient("test-model")
+	c.BaseURL = url + "/v1"
+	return c
+}
+
+func completion(content string) string {
+	b, _ := json.Marshal(map[string]any{
+		"choices": []any{map[string]any{"message": map[string]any{"role": "assistant", "content": content}}},
+	})
+	return string(b)
+}
+
+func TestDowngradeLadder(t *testing.T) {
+	srv := newFakeServer(t, func(req map[string]any) (int, string) {
+		format, _ := req["response_format"].(map[string]any)
+		switch format["type"] {
+		case "json_schema":
+			return http.StatusBadRequest, `{"error": {"message": "response_format type json_schema is not supported"}}`
+		case "json_object":
+			return http.StatusUnprocessableEntity, `{"error": {"message": "unknown response_format"}}`
+		}
+		return http.StatusOK, completion(validAnalysis)
+	})
+	c := newTestClient(srv.URL)
+
+	if _, err := c.complete(context.Background(), "prompt"); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if _, err := c.complete(context.Background(), "prompt"); err != nil {
+		t.Fatalf("second complete: %v", err)
+	}
+
+	want := []string{formatJSONSchema, formatJSONObject, formatNone, formatNone}
+	if got := srv.formats(); !slices.Equal(got, want) {
+		t.Errorf("formats sent = %q, want %q", got, want)
+	}
+}
+
+func TestUnrelatedBadRequestKeepsFormat(t *testing.T) {
+	srv := newFakeServer(t, func(map[string]any) (int, string) {
+		return http.StatusBadRequest, `{"error": {"message": "prompt exceeds the context length"}}`
+	})
+	c := newTestClient(srv.URL)
+
+	_, err := c.complete(context.Background(), "prompt")
+	if !errors.Is(err, ErrModelFailed) {
+		t.Fatalf("err = %v, want ErrModelFailed", err)
+	}
+	if got := srv.formats(); !slices.Equal(got, []string{formatJSONSchema}) {
+		t.Errorf("formats sent = %q, want a single json_schema request", got)
+	}
+	if c.currentFormat() != formatJSONSchema {
+		t.Errorf("format downgraded to %q", c.currentFormat())
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{"unknown model", `{"error": {"message": "The model 'test-model' does not exist"}}`, ErrModelNotFound},
+		{"unknown endpoint", "404 page not found", ErrModelFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeServer(t, func(map[string]any) (int, string) {
+				return http.StatusNotFound, tt.body
+			})
+			_, err := newTestClient(srv.URL).complete(context.Background(), "prompt")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerationOptionsAreSent(t *testing.T) {
+	srv := newFakeServer(t, func(map[string]any) (int, string) {
+		return http.StatusOK, completion(validAnalysis)
+	})
+	c := newTestClient(srv.URL)
+	temperature, seed, topP, maxTokens := 0.0, 0, 0.9, 256
+	c.Generation.Temperature = &temperature
+	c.Generation.Seed = &seed
+	c.Generation.TopP = &topP
+	c.Generation.NumPredict = &maxTokens
+
+	if _, err := c.complete(context.Background(), "prompt"); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	req := srv.requests[0]
+	want := map[string]any{"temperature": 0.0, "seed": 0.0, "top_p": 0.9, "max_tokens": 256.0}
+	for key, v := range want {
+		if got, ok := req[key]; !ok || got != v {
+			t.Errorf("%s = %v (sent: %v), want %v", key, got, ok, v)
+		}
+	}
+}
+
+func TestGenerationOptionsUnsetAreOmitted(t *testing.T) {
+	srv := newFakeServer(t, func(map[string]any) (int, string) {
+		return http.StatusOK, completion(validAnalysis)
+	})
+	if _, err := newTestClient(srv.URL).complete(context.Background(), "prompt"); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	for _, key := range []string{"temperature", "seed", "top_p", "max_tokens"} {
+		if v, ok := srv.requests[0][key]; ok {
+			t.Errorf("%s = %v sent, want it omitted", key, v)
+		}
+	}
+}
+
+func TestConnectionErrors(t *testing.T) {
+	t.Run("connection closed mid-response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			buf.WriteString("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 1000\r\n\r\n{\"choices\":")
+			buf.Flush()
+			conn.Close()
+		}))
+		defer srv.Close()
+
+		_, err := newTestClient(srv.URL).complete(context.Background(), "prompt")
+		if !errors.Is(err, ErrConnectionFailed) || errors.Is(err, ErrServerNotFound) {
+			t.Errorf("err = %v, want ErrConnectionFailed", err)
+		}
+	})
+	t.Run("nothing listening", func(t *testing.T) {
+		srv := httptest.NewServer(nil)
+		srv.Close()
+
+		_, err := newTestClient(srv.URL).complete(context.Background(), "prompt")
+		if !errors.Is(err, ErrServerNotFound) {
+			t.Errorf("err = %v, want ErrServerNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+// Package persona defines the narrators an analysis can be delivered by.
+//
+// A persona bundles a voice for the LLM backends (its system prompt), pools
+// of templates that replace the local engine's titles, summaries,
+// conclusions and closing lines, and the section headings the report is
+// printed under. The default persona, OVERTHINK, overrides nothing.
+//
+// Besides the built-in personas, a persona can be loaded from a JSON file
+// with the same fields; anything the file leaves out keeps OVERTHINK's
+// behaviour.
+package persona
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+// Persona is a narrator voice.
+type Persona struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// SystemPrompt introduces the narrator to LLM backends. The rules that
+	// keep the output well-formed are appended to it. Empty keeps the
+	// OVERTHINK prompt.
+	SystemPrompt string `json:"system_prompt,omitempty"`
+
+	// Headings rename the sections of the report.
+	Headings engine.Headings `json:"headings"`
+
+	// Template pools for the local engine. An empty pool keeps the engine's
+	// own.
+	TitlePrefixes []string `json:"title_prefixes,omitempty"`
+	TitleNouns    []string `json:"title_nouns,omitempty"`
+	Summaries     []string `json:"summaries,omitempty"`
+	Conclusions   []string `json:"conclusions,omitempty"`
+	ClosingLines  []string `json:"closing_lines,omitempty"`
+}
+
+// Default is the name of the standard OVERTHINK persona.
+const Default = "overthink"
+
+// Named returns the built-in persona called name.
+func Named(name string) (*Persona, bool) {
+	for i := range Builtins {
+		if Builtins[i].Name == name {
+			p := Builtins[i]
+			return &p, true
+		}
+	}
+	return nil, false
+}
+
+// Names lists the names of the built-in personas.
+func Names() []string {
+	names := make([]string, len(Builtins))
+	for i, p := range Builtins {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Load reads a persona from a JSON file. A persona without a name is named
+// after the file.
+func Load(path string) (*Persona, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Persona
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return &p, nil
+}
@@ -0,0 +1,187 @@
+// Package trace records the HTTP exchanges with model backends, request and
+// response in full, so that a misbehaving model can be reported with the
+// exact traffic that confused it.
+//
+// Transport wraps another http.RoundTripper. Streaming responses pass
+// through untouched and are copied aside as they are read; each exchange is
+// written out whole once its response body is closed, so exchanges running
+// concurrently never interleave in the trace. Flush writes out the exchanges
+// still in progress, for a run about to exit.
+package trace
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// redacted replaces the values of headers that carry credentials.
+const redacted = "[redacted]"
+
+// secretHeaders are the headers whose values are never written.
+var secretHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// Transport is an http.RoundTripper that writes every exchange to W.
+type Transport struct {
+	// Base performs the requests; nil means http.DefaultTransport.
+	Base http.RoundTripper
+	// W receives the trace.
+	W io.Writer
+
+	mu      sync.Mutex
+	n       int
+	pending map[*exchange]bool
+}
+
+// exchange is the record of one request and its response, kept until it is
+// written out.
+type exchange struct {
+	mu      sync.Mutex
+	record  bytes.Buffer
+	written bool
+}
+
+// add appends p to the record, unless it has already been written.
+func (x *exchange) add(p []byte) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if !x.written {
+		x.record.Write(p)
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	var head bytes.Buffer
+	fmt.Fprintf(&head, "=== %s %s %s\n", start.Format(time.RFC3339Nano), req.Method, req.URL)
+	writeHeaders(&head, req.Header)
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		head.WriteString("\n")
+		head.Write(body)
+		head.WriteString("\n")
+	}
+	x := t.begin(head.Bytes())
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		t.finish(x, fmt.Sprintf("--- error after %s: %v\n\n", time.Since(start).Round(time.Millisecond), err))
+		return nil, err
+	}
+
+	var status bytes.Buffer
+	fmt.Fprintf(&status, "--- %s after %s\n", resp.Status, time.Since(start).Round(time.Millisecond))
+	writeHeaders(&status, resp.Header)
+	status.WriteString("\n")
+	x.add(status.Bytes())
+	resp.Body = &recordingBody{ReadCloser: resp.Body, x: x, done: func() { t.finish(x, "\n\n") }}
+	return resp, nil
+}
+
+// Flush writes out every exchange still in progress, marked as incomplete,
+// so that a run that exits early loses none of its trace. Whatever arrives
+// for those exchanges afterwards is not written.
+func (t *Transport) Flush() {
+	t.mu.Lock()
+	pending := make([]*exchange, 0, len(t.pending))
+	for x := range t.pending {
+		pending = append(pending, x)
+	}
+	t.mu.Unlock()
+	for _, x := range pending {
+		t.finish(x, "\n--- incomplete: the run ended before the exchange did\n\n")
+	}
+}
+
+// base returns the transport that performs the requests.
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// begin starts the record of an exchange with the request described by head.
+func (t *Transport) begin(head []byte) *exchange {
+	x := &exchange{}
+	x.record.Write(head)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending == nil {
+		t.pending = make(map[*exchange]bool)
+	}
+	t.pending[x] = true
+	return x
+}
+
+// finish ends the record of x with trailer and appends it to the trace as
+// the next numbered exchange. Only the first call for an exchange writes.
+func (t *Transport) finish(x *exchange, trailer string) {
+	x.mu.Lock()
+	if x.written {
+		x.mu.Unlock()
+		return
+	}
+	x.written = true
+	x.record.WriteString(trailer)
+	x.mu.Unlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, x)
+	t.n++
+	fmt.Fprintf(t.W, "#%d ", t.n)
+	t.W.Write(x.record.Bytes())
+}
+
+// writeHeaders writes header in a stable order, redacting credentials.
+func writeHeaders(w io.Writer, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			if secretHeaders[name] {
+				value = redacted
+			}
+			fmt.Fprintf(w, "%s: %s\n", name, value)
+		}
+	}
+}
+
+// recordingBody copies a response body into the exchange's record as it is
+// read, and finishes the record once the body is closed.
+type recordingBody struct {
+	io.ReadCloser
+	x    *exchange
+	done func()
+	once sync.Once
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.x.add(p[:n])
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.done)
+	return err
+}
@@ -0,0 +1,133 @@
+package trace
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of a Transport.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// elapsed matches the status line of a response and captures its timing.
+var elapsed = regexp.MustCompile(`(?m)^--- 200 OK after (\S+)$`)
+
+func TestRoundTrip(t *testing.T) {
+	const delay = 20 * time.Millisecond
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"response": "ok"}`)
+	}))
+	defer srv.Close()
+
+	var out syncBuffer
+	client := &http.Client{Transport: &Transport{Base: srv.Client().Transport, W: &out}}
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/generate", strings.NewReader(`{"model": "llama3"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"response": "ok"}` {
+		t.Errorf("body passed through as %q", body)
+	}
+
+	trace := out.String()
+	for _, want := range []string{
+		"#1 === ",
+		" POST " + srv.URL + "/api/generate\n",
+		"Authorization: [redacted]\n",
+		`{"model": "llama3"}`,
+		"Content-Type: application/json\n",
+		`{"response": "ok"}`,
+	} {
+		if !strings.Contains(trace, want) {
+			t.Errorf("trace lacks %q:\n%s", want, trace)
+		}
+	}
+	if strings.Contains(trace, "secret") {
+		t.Errorf("trace leaks the credentials:\n%s", trace)
+	}
+	m := elapsed.FindStringSubmatch(trace)
+	if m == nil {
+		t.Fatalf("trace has no status line:\n%s", trace)
+	}
+	if d, err := time.ParseDuration(m[1]); err != nil || d < delay {
+		t.Errorf("recorded timing %q, want at least %s", m[1], delay)
+	}
+}
+
+func TestRoundTripError(t *testing.T) {
+	srv := httptest.NewServer(nil)
+	srv.Close()
+
+	var out syncBuffer
+	client := &http.Client{Transport: &Transport{W: &out}}
+	if _, err := client.Get(srv.URL); err == nil {
+		t.Fatal("request to a closed server succeeded")
+	}
+	if trace := out.String(); !strings.Contains(trace, "#1 === ") || !strings.Contains(trace, "--- error after ") {
+		t.Errorf("trace does not record the failure:\n%s", trace)
+	}
+}
+
+func TestFlushWritesExchangesInProgress(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "first chunk\n")
+		w.(http.Flusher).Flush()
+		<-release
+		io.WriteString(w, "second chunk\n")
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	var out syncBuffer
+	tr := &Transport{Base: srv.Client().Transport, W: &out}
+	resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	chunk := make([]byte, len("first chunk\n"))
+	if _, err := io.ReadFull(resp.Body, chunk); err != nil {
+		t.Fatal(err)
+	}
+
+	tr.Flush()
+	trace := out.String()
+	if !strings.Contains(trace, "first chunk") || !strings.Contains(trace, "--- incomplete") {
+		t.Errorf("flushed trace = %q, want the partial exchange marked incomplete", trace)
+	}
+
+	// The exchange is written once: closing its body later adds nothing.
+	resp.Body.Close()
+	tr.Flush()
+	if got := out.String(); got != trace {
+		t.Errorf("exchange written again:\n%s", got)
+	}
+}
@@ -0,0 +1,336 @@
+// Package tui runs overthink as a full-screen terminal application.
+//
+// The report is revealed with animated bars and a risk index that counts up
+// through the calm, concerning and alarming colors. Sections can be folded,
+// and single keys regenerate the analysis, copy it as Markdown (via the OSC 52
+// clipboard escape, so no external tools are needed) or switch the thinker.
+package tui
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/rishichawda/overthinker/internal/backend"
+	"github.com/rishichawda/overthinker/internal/engine"
+	"github.com/rishichawda/overthinker/internal/ollama"
+	"github.com/rishichawda/overthinker/internal/persona"
+)
+
+// frameInterval is the delay between animation frames.
+const frameInterval = 30 * time.Millisecond
+
+// Terminal control sequences.
+const (
+	enterAltScreen = "\033[?1049h"
+	exitAltScreen  = "\033[?1049l"
+	hideCursor     = "\033[?25l"
+	showCursor     = "\033[?25h"
+	cursorHome     = "\033[H"
+	clearScreen    = "\033[2J"
+)
+
+// ErrNotTerminal is returned when stdin or stdout is not a terminal.
+var ErrNotTerminal = errors.New("the TUI needs an interactive terminal")
+
+// Options configures a TUI session.
+type Options struct {
+	// Question is the question to overthink.
+	Question string
+	// Specs are the --thinker specifications; empty means the local engine.
+	Specs []string
+	// Timeout, Retries, Context, Intensity, Persona, Generation and Aliases
+	// configure the fallback chain, as on the command line.
+	Timeout    time.Duration
+	Retries    int
+	Context    string
+	Intensity  engine.Intensity
+	Persona    *persona.Persona
+	Generation ollama.Generation
+	Aliases    map[string]string
+}
+
+// key is a decoded keypress.
+type key int
+
+const (
+	keyUp key = iota
+	keyDown
+	keyEnter
+	keyEscape
+	keyBackspace
+	keyInterrupt
+	keyRune
+)
+
+type keypress struct {
+	key  key
+	char rune
+}
+
+type analysis struct {
+	result *engine.AnalysisResult
+	name   string
+	err    error
+}
+
+// session is the state of a running TUI.
+type session struct {
+	opts   Options
+	screen *engine.Screen
+	seed   int64
+
+	input   string
+	editing bool
+	busy    bool
+	results chan analysis
+}
+
+// Run takes over the terminal until the user quits.
+func Run(opts Options) error {
+	inFd, outFd := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return ErrNotTerminal
+	}
+
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(os.Stdout, enterAltScreen+hideCursor)
+	defer func() {
+		fmt.Fprint(os.Stdout, showCursor+exitAltScreen)
+		term.Restore(inFd, state)
+	}()
+
+	s := &session{
+		opts:    opts,
+		screen:  engine.NewScreen(thinkerName(opts.Specs)),
+		results: make(chan analysis, 1),
+	}
+	keys := readKeys()
+	ticker := time.NewTicker(frameInterval)
+	defer ticker.Stop()
+
+	s.analyze()
+	s.draw(outFd)
+	for {
+		select {
+		case k, ok := <-keys:
+			if !ok || !s.handle(k) {
+				return nil
+			}
+		case a := <-s.results:
+			s.busy = false
+			if a.err != nil {
+				s.screen.Status = "Failed: " + a.err.Error()
+			} else {
+				s.screen.Thinker = a.name
+				s.screen.Show(a.result)
+			}
+		case <-ticker.C:
+			if !s.screen.Tick() {
+				continue
+			}
+		}
+		s.draw(outFd)
+	}
+}
+
+// analyze starts an analysis in the background; its outcome arrives on
+// s.results.
+func (s *session) analyze() {
+	if s.busy {
+		return
+	}
+	specs := []string{backend.Local}
+	if len(s.opts.Specs) > 0 {
+		specs = append([]string(nil), s.opts.Specs...)
+	}
+	// A regenerated analysis reseeds every thinker: the local engine, seeded
+	// or not, and the sampling of Ollama models.
+	gen := s.opts.Generation
+	if s.seed != 0 {
+		for i, spec := range specs {
+			if backend.IsLocal(spec) {
+				specs[i] = fmt.Sprintf("%s:%d", backend.Local, s.seed)
+			}
+		}
+		seed := int(s.seed)
+		gen.Seed = &seed
+	}
+
+	c, err := backend.NewChain(specs, backend.Options{
+		Timeout:    s.opts.Timeout,
+		Retries:    s.opts.Retries,
+		Context:    s.opts.Context,
+		Intensity:  s.opts.Intensity,
+		Persona:    s.opts.Persona,
+		Generation: gen,
+		Aliases:    s.opts.Aliases,
+	})
+	if err != nil {
+		s.screen.Status = err.Error()
+		return
+	}
+
+	s.busy = true
+	s.screen.Show(nil)
+	s.screen.Thinker = thinkerName(s.opts.Specs)
+	name, question := s.screen.Thinker, s.opts.Question
+	go func() {
+		result, err := c.Analyze(question)
+		if err == nil && len(result.Attempts) > 0 {
+			name = result.Attempts[len(result.Attempts)-1].Thinker
+		}
+		s.results <- analysis{result: result, name: name, err: err}
+	}()
+}
+
+// handle applies a keypress. It returns false when the TUI should exit.
+func (s *session) handle(k keypress) bool {
+	if s.editing {
+		return s.handleInput(k)
+	}
+
+	s.screen.Status = ""
+	switch {
+	case k.key == keyInterrupt, k.key == keyEscape, k.key == keyRune && (k.char == 'q' || k.char == 'Q'):
+		return false
+	case k.key == keyUp, k.key == keyRune && k.char == 'k':
+		s.screen.FocusPrev()
+	case k.key == keyDown, k.key == keyRune && (k.char == 'j' || k.char == '\t'):
+		s.screen.FocusNext()
+	case k.key == keyEnter, k.key == keyRune && k.char == ' ':
+		if s.screen.Animating() {
+			s.screen.Skip()
+		} else {
+			s.screen.Toggle()
+		}
+	case k.key == keyRune && k.char == 'r':
+		s.seed = rand.Int63()
+		s.analyze()
+	case k.key == keyRune && k.char == 'c':
+		s.copyMarkdown()
+	case k.key == keyRune && k.char == 't':
+		s.editing = true
+		s.input = strings.Join(s.opts.Specs, ",")
+		s.screen.Prompt = "thinker> " + s.input
+	}
+	return true
+}
+
+// handleInput edits the inline thinker prompt.
+func (s *session) handleInput(k keypress) bool {
+	switch k.key {
+	case keyInterrupt:
+		return false
+	case keyEscape:
+		s.editing = false
+		s.screen.Prompt = ""
+		return true
+	case keyEnter:
+		s.editing = false
+		s.screen.Prompt = ""
+		s.opts.Specs = backend.Split(s.input)
+		s.analyze()
+		return true
+	case keyBackspace:
+		if r := []rune(s.input); len(r) > 0 {
+			s.input = string(r[:len(r)-1])
+		}
+	case keyRune:
+		s.input += string(k.char)
+	}
+	s.screen.Prompt = "thinker> " + s.input
+	return true
+}
+
+// copyMarkdown places the report on the clipboard using the OSC 52 escape,
+// which most modern terminals (and tmux, with set-clipboard on) honour.
+func (s *session) copyMarkdown() {
+	if s.screen.Result == nil {
+		return
+	}
+	md := engine.RenderMarkdown(s.screen.Result)
+	fmt.Fprintf(os.Stdout, "\033]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(md)))
+	s.screen.Status = "Copied report as Markdown"
+}
+
+func (s *session) draw(fd int) {
+	width, height, err := term.GetSize(fd)
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+	fmt.Fprint(os.Stdout, cursorHome+clearScreen+s.screen.Render(width, height))
+}
+
+func thinkerName(specs []string) string {
+	if len(specs) == 0 {
+		return backend.Local
+	}
+	return strings.Join(specs, ",")
+}
+
+// readKeys decodes keypresses from stdin until it is closed.
+func readKeys() <-chan keypress {
+	keys := make(chan keypress)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 64)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			for _, k := range decodeKeys(buf[:n]) {
+				keys <- k
+			}
+		}
+	}()
+	return keys
+}
+
+// decodeKeys splits one read from a raw-mode terminal into keypresses.
+func decodeKeys(b []byte) []keypress {
+	var keys []keypress
+	for len(b) > 0 {
+		switch {
+		case len(b) >= 3 && b[0] == 0x1b && (b[1] == '[' || b[1] == 'O'):
+			switch b[2] {
+			case 'A':
+				keys = append(keys, keypress{key: keyUp})
+			case 'B':
+				keys = append(keys, keypress{key: keyDown})
+			}
+			b = b[3:]
+		case b[0] == 0x1b:
+			keys = append(keys, keypress{key: keyEscape})
+			b = b[1:]
+		case b[0] == 3 || b[0] == 4:
+			keys = append(keys, keypress{key: keyInterrupt})
+			b = b[1:]
+		case b[0] == '\r' || b[0] == '\n':
+			keys = append(keys, keypress{key: keyEnter})
+			b = b[1:]
+		case b[0] == 127 || b[0] == 8:
+			keys = append(keys, keypress{key: keyBackspace})
+			b = b[1:]
+		default:
+			// Invalid bytes, and runes split across reads, are dropped.
+			r, size := utf8.DecodeRune(b)
+			if r != utf8.RuneError || size > 1 {
+				keys = append(keys, keypress{key: keyRune, char: r})
+			}
+			b = b[size:]
+		}
+	}
+	return keys
+}
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rishichawda/overthinker/internal/engine"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	r := func(c rune) keypress { return keypress{key: keyRune, char: c} }
+	k := func(k key) keypress { return keypress{key: k} }
+	tests := []struct {
+		name string
+		in   string
+		want []keypress
+	}{
+		{"arrows", "\x1b[A\x1b[B\x1bOA", []keypress{k(keyUp), k(keyDown), k(keyUp)}},
+		{"unknown sequence", "\x1b[C", nil},
+		{"escape", "\x1b", []keypress{k(keyEscape)}},
+		{"escape then rune", "\x1bq", []keypress{k(keyEscape), r('q')}},
+		{"ctrl-c and ctrl-d", "\x03\x04", []keypress{k(keyInterrupt), k(keyInterrupt)}},
+		{"enter", "\r\n", []keypress{k(keyEnter), k(keyEnter)}},
+		{"backspace", "\x7f\x08", []keypress{k(keyBackspace), k(keyBackspace)}},
+		{"ascii", "rq", []keypress{r('r'), r('q')}},
+		{"multibyte", "é€😀", []keypress{r('é'), r('€'), r('😀')}},
+		{"truncated rune", "a\xc3", []keypress{r('a')}},
+		{"split rune", "\xa9b", []keypress{r('b')}},
+		{"truncated sequence", "\x1b[", []keypress{k(keyEscape), r('[')}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeKeys([]byte(tt.in)); !slices.Equal(got, tt.want) {
+				t.Errorf("decodeKeys(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestSession() *session {
+	return &session{
+		screen:  engine.NewScreen(thinkerName(nil)),
+		results: make(chan analysis, 1),
+	}
+}
+
+func TestHandle(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      keypress
+		wantExit bool
+	}{
+		{"q quits", keypress{key: keyRune, char: 'q'}, true},
+		{"Q quits", keypress{key: keyRune, char: 'Q'}, true},
+		{"escape quits", keypress{key: keyEscape}, true},
+		{"ctrl-c quits", keypress{key: keyInterrupt}, true},
+		{"down stays", keypress{key: keyDown}, false},
+		{"enter stays", keypress{key: keyEnter}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSession()
+			s.screen.Status = "old"
+			if got := s.handle(tt.key); got == tt.wantExit {
+				t.Errorf("handle = %v, want %v", got, !tt.wantExit)
+			}
+			if s.screen.Status != "" {
+				t.Errorf("status not cleared: %q", s.screen.Status)
+			}
+		})
+	}
+}
+
+func TestHandleRegenerate(t *testing.T) {
+	s := newTestSession()
+	s.handle(keypress{key: keyRune, char: 'r'})
+	if s.seed == 0 || !s.busy {
+		t.Fatalf("regenerate: seed = %d, busy = %v", s.seed, s.busy)
+	}
+	if a := <-s.results; a.err != nil || a.result == nil {
+		t.Errorf("regenerated analysis: %+v", a)
+	}
+}
+
+func TestHandleInput(t *testing.T) {
+	s := newTestSession()
+	s.opts.Specs = []string{"llama3"}
+	s.handle(keypress{key: keyRune, char: 't'})
+	if !s.editing || s.input != "llama3" {
+		t.Fatalf("t: editing = %v, input = %q", s.editing, s.input)
+	}
+
+	for _, k := range []keypress{
+		{key: keyBackspace}, {key: keyBackspace}, {key: keyBackspace},
+		{key: keyBackspace}, {key: keyBackspace}, {key: keyBackspace},
+		{key: keyBackspace}, // one more than the input holds
+		{key: keyRune, char: 'l'}, {key: keyRune, char: 'o'}, {key: keyRune, char: 'c'},
+		{key: keyRune, char: 'a'}, {key: keyRune, char: 'l'}, {key: keyRune, char: 'é'},
+		{key: keyBackspace},
+	} {
+		if !s.handle(k) {
+			t.Fatalf("handle(%v) exited", k)
+		}
+	}
+	if s.input != "local" || s.screen.Prompt != "thinker> local" {
+		t.Fatalf("input = %q, prompt = %q", s.input, s.screen.Prompt)
+	}
+
+	s.handle(keypress{key: keyEnter})
+	if s.editing || s.screen.Prompt != "" || !slices.Equal(s.opts.Specs, []string{"local"}) {
+		t.Errorf("enter: editing = %v, prompt = %q, specs = %q", s.editing, s.screen.Prompt, s.opts.Specs)
+	}
+	<-s.results
+}
+
+func TestHandleInputEscapeAndInterrupt(t *testing.T) {
+	s := newTestSession()
+	s.opts.Specs = []string{"llama3"}
+	s.handle(keypress{key: keyRune, char: 't'})
+	s.handle(keypress{key: keyRune, char: 'x'})
+	if !s.handle(keypress{key: keyEscape}) || s.editing {
+		t.Fatal("escape should leave editing without exiting")
+	}
+	if !slices.Equal(s.opts.Specs, []string{"llama3"}) {
+		t.Errorf("escape changed specs to %q", s.opts.Specs)
+	}
+
+	s.handle(keypress{key: keyRune, char: 't'})
+	if s.handle(keypress{key: keyInterrupt}) {
+		t.Error("ctrl-c while editing should exit")
+	}
+}
@@ -11,6 +11,12 @@ func NewRand() *rand.Rand {
 	return rand.New(rand.NewSource(time.Now().UnixNano()))
 }
 
+// NewSeededRand creates a random source from a fixed seed, so that the same
+// seed always reproduces the same sequence.
+func NewSeededRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
 // PickString selects a pseudo-random element from a string slice.
 func PickString(rng *rand.Rand, pool []string) string {
 	if len(pool) == 0 {